@@ -13,10 +13,13 @@ import (
 
 	"github.com/mcpregistry/server/internal/api"
 	"github.com/mcpregistry/server/internal/config"
+	"github.com/mcpregistry/server/internal/gitauth"
 	"github.com/mcpregistry/server/internal/github"
 	"github.com/mcpregistry/server/internal/gitstore"
+	"github.com/mcpregistry/server/internal/localstore"
 	"github.com/mcpregistry/server/internal/middleware"
 	"github.com/mcpregistry/server/internal/registry"
+	"github.com/mcpregistry/server/internal/source"
 	"github.com/mcpregistry/server/internal/sync"
 )
 
@@ -41,55 +44,55 @@ func run(logger *slog.Logger) error {
 	}
 
 	logger.Info("starting MCP registry server",
+		"source", cfg.Source,
+		"git_auth", cfg.GitAuth,
 		"repo_url", cfg.RegistryRepoURL,
 		"branch", cfg.RegistryBranch,
+		"tag_pattern", cfg.RegistryTagPattern,
+		"commit", cfg.RegistryCommit,
+		"local_path", cfg.LocalPath,
+		"git_storage", cfg.GitStorage,
 		"clone_timeout", cfg.CloneTimeout,
+		"cache_mode", cfg.CacheMode,
 		"cache_size", cfg.CacheSize,
+		"index_mode", cfg.IndexMode,
 	)
 
-	// Initialize GitHub App authentication
-	ghAuth, err := github.NewAppAuth(
-		cfg.GitHubAppID,
-		cfg.GitHubAppPrivateKey,
-		cfg.GitHubInstallationID,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to initialize GitHub App auth: %w", err)
-	}
-
-	// Create context with clone timeout for initial setup
-	cloneCtx, cloneCancel := context.WithTimeout(context.Background(), cfg.CloneTimeout)
-	defer cloneCancel()
-
-	// Initialize git store with disk-based storage
-	store, err := gitstore.New(gitstore.Config{
-		RepoURL:   cfg.RegistryRepoURL,
-		Branch:    cfg.RegistryBranch,
-		LocalPath: cfg.DataPath,
-		Auth:      ghAuth,
-		Logger:    logger,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create git store: %w", err)
-	}
-
-	// Perform initial clone
-	logger.Info("cloning registry repository", "timeout", cfg.CloneTimeout)
-	if err := store.Clone(cloneCtx); err != nil {
-		logger.Error("failed to clone repository",
-			"error", err,
-			"repo_url", cfg.RegistryRepoURL,
-			"timeout", cfg.CloneTimeout,
-		)
-		return fmt.Errorf("failed to clone repository within %s: %w", cfg.CloneTimeout, err)
+	var src source.Source
+	var localStore *localstore.Store
+	var webhookRef string
+	switch cfg.Source {
+	case config.SourceLocal:
+		localStore, err = localstore.New(localstore.Config{
+			Path:         cfg.LocalPath,
+			PollInterval: cfg.LocalPollInterval,
+			Logger:       logger,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open local registry: %w", err)
+		}
+		src = localStore
+		logger.Info("serving local registry", "path", cfg.LocalPath, "revision", localStore.CurrentRevision())
+	default:
+		store, err := openGitStore(cfg, logger)
+		if err != nil {
+			return err
+		}
+		src = store
+		webhookRef = store.WebhookRef()
 	}
-	logger.Info("repository cloned successfully", "commit", store.CurrentCommit())
 
-	// Initialize server registry with LRU cache
+	// Initialize server registry, preloaded or backed by an LRU cache
 	reg, err := registry.New(registry.Config{
-		Store:     store,
+		Store:     src,
 		CacheSize: cfg.CacheSize,
+		CacheMode: cfg.CacheMode,
+		IndexMode: cfg.IndexMode,
+		ScanDir:   cfg.ScanDir,
 		Logger:    logger,
+
+		ValidationPolicy:  cfg.ValidationPolicy,
+		SnapshotRetention: cfg.SnapshotRetention,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize registry: %w", err)
@@ -97,9 +100,10 @@ func run(logger *slog.Logger) error {
 
 	// Load and validate index
 	if err := reg.LoadIndex(); err != nil {
-		logger.Error("failed to load index.yaml",
+		logger.Error("failed to load index",
 			"error", err,
-			"message", "index.yaml is required - ensure CI generates it on merge",
+			"index_mode", cfg.IndexMode,
+			"message", "index.yaml is required in file mode - ensure CI generates it on merge or set INDEX_MODE=scan",
 		)
 		return fmt.Errorf("failed to load index: %w", err)
 	}
@@ -107,10 +111,12 @@ func run(logger *slog.Logger) error {
 
 	// Initialize sync manager
 	syncMgr := sync.NewManager(sync.Config{
-		Store:        store,
+		Source:       src,
 		Registry:     reg,
 		PollInterval: cfg.PollInterval,
 		Debounce:     10 * time.Second,
+		StaleRetry:   cfg.StaleRetryInterval,
+		EventHistory: cfg.EventHistory,
 		Logger:       logger,
 	})
 
@@ -125,6 +131,7 @@ func run(logger *slog.Logger) error {
 		Registry:      reg,
 		SyncManager:   syncMgr,
 		WebhookSecret: cfg.WebhookSecret,
+		WebhookRef:    webhookRef,
 		Logger:        logger,
 	})
 
@@ -136,11 +143,15 @@ func run(logger *slog.Logger) error {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	srv.RegisterOnShutdown(syncMgr.Events().Close)
 
 	// Start sync manager
 	syncCtx, syncCancel := context.WithCancel(context.Background())
 	defer syncCancel()
 	go syncMgr.Start(syncCtx)
+	if localStore != nil {
+		go localStore.Watch(syncCtx, syncMgr.Trigger)
+	}
 
 	// Start server in goroutine
 	errChan := make(chan error, 1)
@@ -181,3 +192,97 @@ func run(logger *slog.Logger) error {
 	logger.Info("server stopped gracefully")
 	return nil
 }
+
+// newAuthProvider creates the git credentials provider for the configured mode
+func newAuthProvider(cfg *config.Config) (gitauth.Provider, error) {
+	switch cfg.GitAuth {
+	case config.AuthToken:
+		return gitauth.NewToken(cfg.GitTokenUsername, cfg.GitToken)
+
+	case config.AuthSSH:
+		var knownHosts []string
+		if cfg.SSHKnownHostsPath != "" {
+			knownHosts = append(knownHosts, cfg.SSHKnownHostsPath)
+		}
+		return gitauth.NewSSHKey(cfg.SSHUser, cfg.SSHPrivateKey, cfg.SSHKeyPassphrase, knownHosts...)
+
+	case config.AuthNone:
+		return gitauth.Anonymous{}, nil
+
+	default:
+		ghAuth, err := github.NewAppAuth(
+			cfg.GitHubAppID,
+			cfg.GitHubAppPrivateKey,
+			cfg.GitHubInstallationID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return gitauth.NewGitHubApp(ghAuth), nil
+	}
+}
+
+// openGitStore sets up git authentication and performs the initial clone
+func openGitStore(cfg *config.Config, logger *slog.Logger) (*gitstore.Store, error) {
+	auth, err := newAuthProvider(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s auth: %w", cfg.GitAuth, err)
+	}
+
+	var verifier gitstore.Verifier
+	if cfg.VerifySignatures() {
+		v, err := gitstore.NewSignatureVerifier(cfg.SigningKeyringPath, cfg.AllowedSignersPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize signature verification: %w", err)
+		}
+		verifier = v
+		logger.Info("commit signature verification enabled",
+			"gpg_keyring", cfg.SigningKeyringPath,
+			"allowed_signers", cfg.AllowedSignersPath,
+		)
+	}
+
+	// Create context with clone timeout for initial setup
+	cloneCtx, cloneCancel := context.WithTimeout(context.Background(), cfg.CloneTimeout)
+	defer cloneCancel()
+
+	// Initialize git store with disk-based storage
+	store, err := gitstore.New(gitstore.Config{
+		RepoURL:    cfg.RegistryRepoURL,
+		Branch:     cfg.RegistryBranch,
+		TagPattern: cfg.RegistryTagPattern,
+		Commit:     cfg.RegistryCommit,
+		LocalPath:  cfg.DataPath,
+		InMemory:   cfg.GitStorage == config.StorageMemory,
+		Auth:       auth,
+		Verifier:   verifier,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create git store: %w", err)
+	}
+
+	// Perform initial clone
+	logger.Info("initializing registry repository", "timeout", cfg.CloneTimeout)
+	if err := store.Clone(cloneCtx); err != nil {
+		logger.Error("failed to clone repository",
+			"error", err,
+			"repo_url", cfg.RegistryRepoURL,
+			"timeout", cfg.CloneTimeout,
+		)
+		return nil, fmt.Errorf("failed to clone repository within %s: %w", cfg.CloneTimeout, err)
+	}
+	if store.Stale() {
+		logger.Warn("serving stale on-disk commit, will keep retrying in the background",
+			"commit", store.CurrentCommit(),
+			"reason", store.StaleReason(),
+		)
+	} else {
+		logger.Info("repository cloned successfully",
+			"commit", store.CurrentCommit(),
+			"tag", store.CurrentTag(),
+		)
+	}
+
+	return store, nil
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// atomFeed is an Atom (RFC 4287) rendering of a changes response
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Links   []atomLink `xml:"link"`
+	Summary string     `xml:"summary"`
+}
+
+// wantsAtom reports whether a request asks for the Atom rendering
+func wantsAtom(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "atom" ||
+		strings.Contains(r.Header.Get("Accept"), "application/atom+xml")
+}
+
+// writeAtom renders the changes between two commits as an Atom feed, one
+// entry per server. Entries without a known change time take the feed's.
+func writeAtom(w http.ResponseWriter, r *http.Request, changes *domain.ChangesResponse) {
+	var updated time.Time
+	for _, c := range changes.Changes {
+		if c.UpdatedAt.After(updated) {
+			updated = c.UpdatedAt
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	feed := atomFeed{
+		ID:      "urn:mcp-registry:changes:" + changes.From + ".." + changes.To,
+		Title:   "MCP registry changes",
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "MCP registry"},
+		Links:   []atomLink{{Rel: "self", Href: r.URL.RequestURI()}},
+		Entries: make([]atomEntry, 0, len(changes.Changes)),
+	}
+
+	for _, c := range changes.Changes {
+		commit := c.Commit
+		if commit == "" {
+			commit = changes.To
+		}
+		entryUpdated := c.UpdatedAt
+		if entryUpdated.IsZero() {
+			entryUpdated = updated
+		}
+
+		summary := make([]string, 0, len(c.Fields))
+		for _, f := range c.Fields {
+			summary = append(summary, f.Description)
+		}
+		if len(summary) == 0 {
+			summary = append(summary, c.Name+" "+c.Type)
+		}
+
+		entry := atomEntry{
+			ID:      "urn:mcp-registry:change:" + commit + ":" + c.Name,
+			Title:   c.Name + " " + c.Type,
+			Updated: entryUpdated.UTC().Format(time.RFC3339),
+			Summary: strings.Join(summary, "; "),
+		}
+		if c.Type != domain.ChangeRemoved {
+			entry.Links = []atomLink{{Href: "/v0.1/servers/" + url.PathEscape(c.Name)}}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(feed)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/registry"
+)
+
+func TestWriteAtom(t *testing.T) {
+	changed := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	changes := &domain.ChangesResponse{
+		From: "aaaa",
+		To:   "bbbb",
+		Changes: []domain.ServerChange{
+			{
+				Name:      "com.example/weather",
+				Type:      domain.ChangeModified,
+				Commit:    "cccc",
+				UpdatedAt: changed,
+				Fields: []domain.FieldChange{
+					{Description: "remote URL changed"},
+					{Description: "new required env var API_KEY"},
+				},
+			},
+			{Name: "com.example/old", Type: domain.ChangeRemoved},
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	writeAtom(rec, httptest.NewRequest(http.MethodGet, "/v0.1/changes?since=aaaa&format=atom", nil), changes)
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/atom+xml") {
+		t.Errorf("Content-Type = %q, want Atom", ct)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("decode feed: %v\n%s", err, rec.Body)
+	}
+
+	if feed.ID != "urn:mcp-registry:changes:aaaa..bbbb" || feed.Updated != "2025-03-01T12:00:00Z" {
+		t.Errorf("feed id %q updated %q", feed.ID, feed.Updated)
+	}
+	if len(feed.Links) != 1 || feed.Links[0].Rel != "self" || feed.Links[0].Href != "/v0.1/changes?since=aaaa&format=atom" {
+		t.Errorf("feed links = %+v, want a self link", feed.Links)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("feed has %d entries, want 2", len(feed.Entries))
+	}
+
+	modified := feed.Entries[0]
+	if modified.ID != "urn:mcp-registry:change:cccc:com.example/weather" ||
+		modified.Summary != "remote URL changed; new required env var API_KEY" ||
+		len(modified.Links) != 1 || modified.Links[0].Href != "/v0.1/servers/com.example%2Fweather" {
+		t.Errorf("modified entry = %+v", modified)
+	}
+
+	// Removed servers have no page to link to, and entries without a
+	// change time take the feed's
+	removed := feed.Entries[1]
+	if removed.ID != "urn:mcp-registry:change:bbbb:com.example/old" || removed.Summary != "com.example/old removed" ||
+		len(removed.Links) != 0 || removed.Updated != feed.Updated {
+		t.Errorf("removed entry = %+v", removed)
+	}
+}
+
+func TestChangesErrors(t *testing.T) {
+	reg := newTestRegistry(t, writeFiles(t, "", lifecycleFiles), registry.Config{})
+
+	problem(t, get(t, reg, "/v0.1/changes"), http.StatusBadRequest)
+	// A local directory has no history to compare
+	problem(t, get(t, reg, "/v0.1/changes?since=2025-01-01T00:00:00Z"), http.StatusNotImplemented)
+}
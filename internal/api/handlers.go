@@ -2,17 +2,20 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"runtime/debug"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/mcpregistry/server/internal/domain"
 	"github.com/mcpregistry/server/internal/registry"
+	"github.com/mcpregistry/server/internal/source"
 )
 
 // Build information (set at compile time)
@@ -41,23 +44,53 @@ func NewHandlers(reg *registry.Registry, logger *slog.Logger) *Handlers {
 
 // Health returns health check information
 func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
-	store := h.registry.Store()
+	src := h.registry.Source()
+	info := src.Info()
 
 	status := "ok"
 	indexStatus := h.registry.IndexStatus()
-	if indexStatus != "valid" {
+	rejection := h.registry.LastRejection()
+	sr, ok := src.(source.StaleReporter)
+	stale := ok && sr.Stale()
+	var staleReason string
+	if stale {
+		staleReason = sr.StaleReason()
+	}
+	signature := commitSignature(src)
+	quarantined := h.registry.Quarantined()
+	if indexStatus != "valid" || rejection != nil || stale || len(quarantined) > 0 {
+		status = "degraded"
+	}
+	if signature != nil && !signature.Verified {
 		status = "degraded"
 	}
 
 	resp := domain.HealthResponse{
-		Status:      status,
-		RepoURL:     store.RepoURL(),
-		Branch:      store.Branch(),
-		CommitSHA:   store.CurrentCommit(),
-		LastSyncAt:  h.registry.LastSyncAt().Format(time.RFC3339),
-		IndexStatus: indexStatus,
-		ServerCount: h.registry.ServerCount(),
-		CacheStats:  h.registry.CacheStats(),
+		Status:        status,
+		Source:        info.Type,
+		RepoURL:       info.Location,
+		Tag:           info.Tag,
+		CommitSHA:     h.registry.Revision(),
+		Stale:         stale,
+		StaleReason:   staleReason,
+		LastSyncAt:    h.registry.LastSyncAt().Format(time.RFC3339),
+		IndexStatus:   indexStatus,
+		IndexMode:     h.registry.IndexMode(),
+		IndexDrift:    h.registry.IndexDrift(),
+		ServerCount:   h.registry.ServerCount(),
+		CacheStats:    h.registry.CacheStats(),
+		LastRejection: rejection,
+		Quarantined:   quarantined,
+		Validation:    h.registry.ValidationSummary(),
+		Signature:     signature,
+	}
+	switch info.Mode {
+	case "tag":
+		resp.TagPattern = info.Ref
+	case "commit":
+		resp.PinnedCommit = info.Ref
+	default:
+		resp.Branch = info.Ref
 	}
 
 	writeJSON(w, http.StatusOK, resp)
@@ -106,15 +139,154 @@ func (h *Handlers) ListServers(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	resp, err := h.registry.ListServers(cursor, limit)
+	filter, detail := parseFilter(r.URL.Query())
+	if detail != nil {
+		writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Invalid filter", *detail)
+		return
+	}
+
+	revision, detail := schemaRevision(r)
+	if detail != nil {
+		writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Invalid schema", *detail)
+		return
+	}
+
+	labels, err := registry.ParseSelector(r.URL.Query().Get("labels"))
 	if err != nil {
+		writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Invalid label selector",
+			domain.ErrorDetail{
+				Message:  err.Error(),
+				Location: "query.labels",
+				Value:    r.URL.Query().Get("labels"),
+			})
+		return
+	}
+
+	var includeDeleted bool
+	if v := r.URL.Query().Get("include_deleted"); v != "" {
+		includeDeleted, err = strconv.ParseBool(v)
+		if err != nil {
+			writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Invalid include_deleted",
+				domain.ErrorDetail{
+					Message:  "must be true or false",
+					Location: "query.include_deleted",
+					Value:    v,
+				})
+			return
+		}
+	}
+
+	var updatedSince time.Time
+	if v := r.URL.Query().Get("updated_since"); v != "" {
+		updatedSince, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Invalid updated_since",
+				domain.ErrorDetail{
+					Message:  "must be an RFC 3339 timestamp",
+					Location: "query.updated_since",
+					Value:    v,
+				})
+			return
+		}
+	}
+
+	body, err := h.registry.ListServersJSON(registry.ListOptions{
+		Cursor: cursor,
+		Limit:  limit,
+		Search: r.URL.Query().Get("search"),
+		Filter: filter,
+		Labels: labels,
+
+		UpdatedSince:   updatedSince,
+		IncludeDeleted: includeDeleted,
+	})
+	switch {
+	case errors.Is(err, registry.ErrInvalidCursor):
+		writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Invalid cursor",
+			domain.ErrorDetail{
+				Message:  err.Error(),
+				Location: "query.cursor",
+				Value:    cursor,
+			})
+		return
+	case errors.Is(err, registry.ErrCursorExpired):
+		writeErrorDetails(w, http.StatusGone, "Gone", "Cursor expired",
+			domain.ErrorDetail{
+				Message:  err.Error(),
+				Location: "query.cursor",
+				Value:    cursor,
+			})
+		return
+	case err != nil:
 		h.logger.Error("failed to list servers", "error", err)
+		writeError(w, http.StatusServiceUnavailable, "Service Unavailable", h.indexUnavailable())
+		return
+	}
+
+	h.writeServerJSON(w, body, revision, true)
+}
+
+// Syncs returns what recent syncs changed, for diagnosing cache invalidation
+func (h *Handlers) Syncs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, domain.SyncDiffsResponse{Syncs: h.registry.SyncDiffs()})
+}
+
+// Changes lists the servers that changed since a commit or timestamp, as
+// JSON or as an Atom feed
+func (h *Handlers) Changes(w http.ResponseWriter, r *http.Request) {
+	since := r.URL.Query().Get("since")
+	if since == "" {
+		writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Missing since",
+			domain.ErrorDetail{
+				Message:  "must be a commit hash or an RFC 3339 timestamp",
+				Location: "query.since",
+			})
+		return
+	}
+
+	var commit string
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		commit = since
+	}
+
+	changes, err := h.registry.Changes(commit, sinceTime)
+	switch {
+	case errors.Is(err, source.ErrUnknownCommit):
+		writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Invalid since",
+			domain.ErrorDetail{
+				Message:  err.Error(),
+				Location: "query.since",
+				Value:    since,
+			})
+		return
+	case errors.Is(err, registry.ErrNoHistory):
+		writeError(w, http.StatusNotImplemented, "Not Implemented",
+			"The registry source has no commit history to compare.")
+		return
+	case err != nil:
+		h.logger.Error("failed to list changes", "since", since, "error", err)
 		writeError(w, http.StatusServiceUnavailable, "Service Unavailable",
-			"Index not available. Ensure index.yaml exists and is valid.")
+			"Changes could not be computed.")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	if wantsAtom(r) {
+		writeAtom(w, r, changes)
+		return
+	}
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// Facets returns server counts per facet value for the current commit
+func (h *Handlers) Facets(w http.ResponseWriter, r *http.Request) {
+	facets := h.registry.Facets()
+	if facets == nil {
+		writeError(w, http.StatusServiceUnavailable, "Service Unavailable", h.indexUnavailable())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, facets)
 }
 
 // GetServer returns a server by name (latest version)
@@ -125,13 +297,19 @@ func (h *Handlers) GetServer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	revision, detail := schemaRevision(r)
+	if detail != nil {
+		writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Invalid schema", *detail)
+		return
+	}
+
 	// URL decode the server name
 	decodedName, err := url.PathUnescape(serverName)
 	if err != nil {
 		decodedName = serverName
 	}
 
-	server, err := h.registry.GetServer(decodedName)
+	body, err := h.registry.ServerResponseJSON(decodedName)
 	if err != nil {
 		h.logger.Debug("server not found", "name", decodedName, "error", err)
 		writeError(w, http.StatusNotFound, "Not Found",
@@ -139,22 +317,33 @@ func (h *Handlers) GetServer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := domain.ServerResponse{
-		Server: *server,
-		Meta: &domain.ServerMeta{
-			Official: &domain.OfficialMeta{
-				Status:      "active",
-				PublishedAt: h.registry.LastSyncAt(),
-				IsLatest:    true,
-			},
-		},
+	setLifecycleHeaders(w, h.registry.ServerStatus(decodedName))
+	h.writeServerJSON(w, body, revision, false)
+}
+
+// Validation returns the validation report for the served snapshot
+func (h *Handlers) Validation(w http.ResponseWriter, r *http.Request) {
+	report := h.registry.ValidationReport()
+	if report == nil {
+		writeError(w, http.StatusServiceUnavailable, "Service Unavailable", h.indexUnavailable())
+		return
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	if r.URL.Query().Get("invalid") == "true" {
+		filtered := *report
+		filtered.Servers = make([]domain.ServerValidation, 0, report.Invalid)
+		for _, s := range report.Servers {
+			if !s.Valid {
+				filtered.Servers = append(filtered.Servers, s)
+			}
+		}
+		report = &filtered
+	}
+
+	writeJSON(w, http.StatusOK, report)
 }
 
-// GetServerVersions returns available versions for a server
-// Since we only support latest, this returns just the current version
+// GetServerVersions returns every published version of a server
 func (h *Handlers) GetServerVersions(w http.ResponseWriter, r *http.Request) {
 	serverName := chi.URLParam(r, "serverName")
 	if serverName == "" {
@@ -167,29 +356,27 @@ func (h *Handlers) GetServerVersions(w http.ResponseWriter, r *http.Request) {
 		decodedName = serverName
 	}
 
-	server, err := h.registry.GetServer(decodedName)
+	versions, err := h.registry.GetServerVersions(decodedName)
 	if err != nil {
+		h.logger.Debug("server versions not found", "name", decodedName, "error", err)
 		writeError(w, http.StatusNotFound, "Not Found",
 			"Server not found: "+decodedName)
 		return
 	}
 
-	// Return single version since we only support latest
-	versions := []map[string]interface{}{
-		{
-			"version":   server.Version,
-			"is_latest": true,
-		},
+	setLifecycleHeaders(w, h.registry.ServerStatus(decodedName))
+	resp := domain.ServerVersionsResponse{
+		ServerName: decodedName,
+		Versions:   make([]domain.VersionInfo, 0, len(versions)),
+	}
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, v.Info)
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"server_name": server.Name,
-		"versions":    versions,
-	})
+	writeJSON(w, http.StatusOK, resp)
 }
 
-// GetServerVersion returns a specific version of a server
-// Since we only support latest, any version request returns the current version
+// GetServerVersion returns a specific version of a server from git history
 func (h *Handlers) GetServerVersion(w http.ResponseWriter, r *http.Request) {
 	serverName := chi.URLParam(r, "serverName")
 	version := chi.URLParam(r, "version")
@@ -199,38 +386,51 @@ func (h *Handlers) GetServerVersion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	revision, detail := schemaRevision(r)
+	if detail != nil {
+		writeErrorDetails(w, http.StatusBadRequest, "Bad Request", "Invalid schema", *detail)
+		return
+	}
+
 	decodedName, err := url.PathUnescape(serverName)
 	if err != nil {
 		decodedName = serverName
 	}
 
-	server, err := h.registry.GetServer(decodedName)
+	sv, err := h.registry.GetServerVersion(decodedName, version)
 	if err != nil {
+		h.logger.Debug("server version not found",
+			"name", decodedName,
+			"version", version,
+			"error", err,
+		)
 		writeError(w, http.StatusNotFound, "Not Found",
-			"Server not found: "+decodedName)
+			"Version not found: "+decodedName+"@"+version)
 		return
 	}
 
-	// If specific version requested and doesn't match, return 404
-	// (unless "latest" is requested)
-	if version != "latest" && version != server.Version {
-		writeError(w, http.StatusNotFound, "Not Found",
-			"Version not found. This registry only serves the latest version.")
-		return
+	official := &domain.OfficialMeta{
+		PublishedAt: sv.Info.PublishedAt,
+		IsLatest:    sv.Info.IsLatest,
 	}
-
+	// The lifecycle applies to the server, so every version carries it
+	status := h.registry.ServerStatus(decodedName)
+	official.SetStatus(status)
 	resp := domain.ServerResponse{
-		Server: *server,
-		Meta: &domain.ServerMeta{
-			Official: &domain.OfficialMeta{
-				Status:      "active",
-				PublishedAt: h.registry.LastSyncAt(),
-				IsLatest:    true,
-			},
-		},
+		Server: sv.Server,
+		Meta:   &domain.ServerMeta{Official: official},
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	setLifecycleHeaders(w, status)
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		h.logger.Error("failed to encode server version", "name", decodedName, "error", err)
+		writeError(w, http.StatusInternalServerError, "Internal Server Error",
+			"Version could not be encoded: "+decodedName+"@"+version)
+		return
+	}
+	h.writeServerJSON(w, append(body, '\n'), revision, false)
 }
 
 // NotImplemented returns 501 for write endpoints
@@ -247,17 +447,125 @@ func (h *Handlers) NotImplemented(w http.ResponseWriter, r *http.Request) {
 
 // Helper functions
 
+// parseFilter reads the facet filters of a list request. Multi-valued
+// filters take comma-separated alternatives.
+func parseFilter(q url.Values) (registry.Filter, *domain.ErrorDetail) {
+	filter := registry.Filter{
+		RegistryType: splitList(q.Get(registry.FacetRegistryType)),
+		Transport:    splitList(q.Get(registry.FacetTransport)),
+		RuntimeHint:  splitList(q.Get(registry.FacetRuntimeHint)),
+		RemoteType:   splitList(q.Get(registry.FacetRemoteType)),
+	}
+
+	for name, dst := range map[string]**bool{
+		registry.FacetHasRemote:  &filter.HasRemote,
+		registry.FacetHasPackage: &filter.HasPackage,
+	} {
+		v := q.Get(name)
+		if v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, &domain.ErrorDetail{
+				Message:  "must be true or false",
+				Location: "query." + name,
+				Value:    v,
+			}
+		}
+		*dst = &b
+	}
+
+	return filter, nil
+}
+
+// splitList splits a comma-separated query value, dropping empty items
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setLifecycleHeaders announces a deprecated or deleted server with the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and links its
+// replacement as the successor version
+func setLifecycleHeaders(w http.ResponseWriter, status *domain.ServerStatus) {
+	if status == nil {
+		return
+	}
+
+	if status.Since != nil {
+		w.Header().Set("Deprecation", "@"+strconv.FormatInt(status.Since.Unix(), 10))
+	}
+	if status.Sunset != nil {
+		w.Header().Set("Sunset", status.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if status.Replacement != "" {
+		w.Header().Add("Link", `</v0.1/servers/`+url.PathEscape(status.Replacement)+`>; rel="successor-version"`)
+	}
+}
+
+// commitSignature returns the source's last signature check, if it verifies commits
+func commitSignature(src source.Source) *domain.CommitSignature {
+	sr, ok := src.(source.SignatureReporter)
+	if !ok {
+		return nil
+	}
+
+	v := sr.LastVerification()
+	if v == nil {
+		return nil
+	}
+
+	return &domain.CommitSignature{
+		Commit:    v.Commit,
+		Verified:  v.Verified,
+		Method:    v.Method,
+		Signer:    v.Signer,
+		Error:     v.Error,
+		CheckedAt: v.CheckedAt,
+	}
+}
+
+// indexUnavailable explains a missing snapshot in terms of where the
+// configured index mode builds the index from
+func (h *Handlers) indexUnavailable() string {
+	if h.registry.IndexMode() == registry.IndexModeFile {
+		return "Index not available. Ensure index.yaml exists and is valid."
+	}
+	return "Index not available. Ensure " + h.registry.ScanDir() + "/ contains valid server files."
+}
+
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(v)
 }
 
+// writeRawJSON writes an already encoded JSON response
+func writeRawJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
 func writeError(w http.ResponseWriter, status int, title, detail string) {
+	writeErrorDetails(w, status, title, detail)
+}
+
+// writeErrorDetails writes an RFC 9457 problem document, as Huma does
+func writeErrorDetails(w http.ResponseWriter, status int, title, detail string, errs ...domain.ErrorDetail) {
 	resp := domain.ErrorResponse{
 		Status: status,
 		Title:  title,
 		Detail: detail,
+		Errors: errs,
 	}
-	writeJSON(w, status, resp)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/localstore"
+	"github.com/mcpregistry/server/internal/registry"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// serverYAML renders a minimal valid server definition
+func serverYAML(name, description string) string {
+	return "$schema: " + domain.SchemaURL(domain.CurrentSchema) + "\nname: " + name +
+		"\ndescription: " + description + "\nversion: 1.0.0\n"
+}
+
+// lifecycleFiles has an active, a deprecated and a deleted server
+var lifecycleFiles = map[string]string{
+	"index.yaml": `servers:
+  - name: com.example/active
+    path: servers/active.yaml
+  - name: com.example/deleted
+    path: servers/deleted.yaml
+    status:
+      state: deleted
+  - name: com.example/deprecated
+    path: servers/deprecated.yaml
+`,
+	"servers/active.yaml":  serverYAML("com.example/active", "Active"),
+	"servers/deleted.yaml": serverYAML("com.example/deleted", "Deleted"),
+	"servers/deprecated.yaml": serverYAML("com.example/deprecated", "Deprecated") + `status:
+  state: deprecated
+  replacement: com.example/active
+  since: 2025-06-01T00:00:00Z
+  sunset: 2026-01-01T00:00:00Z
+`,
+}
+
+// writeFiles writes files to dir, or to a new directory if dir is empty,
+// and returns it
+func writeFiles(t *testing.T, dir string, files map[string]string) string {
+	t.Helper()
+	if dir == "" {
+		dir = t.TempDir()
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// newTestRegistry serves a directory and loads its index
+func newTestRegistry(t *testing.T, dir string, cfg registry.Config) *registry.Registry {
+	t.Helper()
+	reg := newUnloadedRegistry(t, dir, cfg)
+	if err := reg.LoadIndex(); err != nil {
+		t.Fatalf("load index: %v", err)
+	}
+	return reg
+}
+
+func newUnloadedRegistry(t *testing.T, dir string, cfg registry.Config) *registry.Registry {
+	t.Helper()
+	store, err := localstore.New(localstore.Config{Path: dir, Logger: discardLogger})
+	if err != nil {
+		t.Fatalf("create local store: %v", err)
+	}
+	cfg.Store = store
+	cfg.Logger = discardLogger
+	reg, err := registry.New(cfg)
+	if err != nil {
+		t.Fatalf("create registry: %v", err)
+	}
+	return reg
+}
+
+// resync picks up changes written to the registry's directory
+func resync(t *testing.T, reg *registry.Registry) {
+	t.Helper()
+	if _, err := reg.Source().Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh source: %v", err)
+	}
+	if _, err := reg.Refresh(); err != nil {
+		t.Fatalf("refresh registry: %v", err)
+	}
+}
+
+// get serves a GET request through the router
+func get(t *testing.T, reg *registry.Registry, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	NewRouter(Config{Registry: reg, Logger: discardLogger}).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+	return v
+}
+
+func TestGetServerLifecycleHeaders(t *testing.T) {
+	reg := newTestRegistry(t, writeFiles(t, "", lifecycleFiles), registry.Config{})
+
+	tests := []struct {
+		name        string
+		wantStatus  string
+		deprecation string
+		sunset      string
+		link        string
+	}{
+		{name: "com.example/active", wantStatus: domain.StatusActive},
+		{
+			name:        "com.example/deprecated",
+			wantStatus:  domain.StatusDeprecated,
+			deprecation: "@1748736000",
+			sunset:      "Thu, 01 Jan 2026 00:00:00 GMT",
+			link:        `</v0.1/servers/com.example%2Factive>; rel="successor-version"`,
+		},
+		// Tombstones resolve by name; a local directory dates the state
+		// from the first sync
+		{name: "com.example/deleted", wantStatus: domain.StatusDeleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, reg, "/v0.1/servers/"+url.PathEscape(tt.name))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			resp := decode[domain.ServerResponse](t, rec)
+			if got := resp.Meta.Official.Status; got != tt.wantStatus {
+				t.Errorf("_meta status = %q, want %q", got, tt.wantStatus)
+			}
+
+			deprecation := rec.Header().Get("Deprecation")
+			if tt.wantStatus == domain.StatusDeleted {
+				if deprecation == "" {
+					t.Error("no Deprecation header for a deleted server")
+				}
+			} else if deprecation != tt.deprecation {
+				t.Errorf("Deprecation = %q, want %q", deprecation, tt.deprecation)
+			}
+			if got := rec.Header().Get("Sunset"); got != tt.sunset {
+				t.Errorf("Sunset = %q, want %q", got, tt.sunset)
+			}
+			if got := rec.Header().Get("Link"); got != tt.link {
+				t.Errorf("Link = %q, want %q", got, tt.link)
+			}
+		})
+	}
+}
+
+func TestListServersIncludeDeleted(t *testing.T) {
+	reg := newTestRegistry(t, writeFiles(t, "", lifecycleFiles), registry.Config{})
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		want       []string
+	}{
+		{query: "", wantStatus: http.StatusOK, want: []string{"com.example/active", "com.example/deprecated"}},
+		{query: "?include_deleted=false", wantStatus: http.StatusOK, want: []string{"com.example/active", "com.example/deprecated"}},
+		{query: "?include_deleted=true", wantStatus: http.StatusOK, want: []string{"com.example/active", "com.example/deleted", "com.example/deprecated"}},
+		{query: "?include_deleted=maybe", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := get(t, reg, "/v0.1/servers"+tt.query)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got []string
+			for _, s := range decode[domain.ServerListResponse](t, rec).Servers {
+				got = append(got, s.Server.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("listed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// problem decodes an error response, checking it is a problem document
+func problem(t *testing.T, rec *httptest.ResponseRecorder, status int) domain.ErrorResponse {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, status, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+	resp := decode[domain.ErrorResponse](t, rec)
+	if resp.Status != status {
+		t.Errorf("problem status = %d, want %d", resp.Status, status)
+	}
+	return resp
+}
+
+func TestListServersCursors(t *testing.T) {
+	files := map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/a
+    path: servers/a.yaml
+  - name: com.example/b
+    path: servers/b.yaml
+  - name: com.example/c
+    path: servers/c.yaml
+`,
+		"servers/a.yaml": serverYAML("com.example/a", "A"),
+		"servers/b.yaml": serverYAML("com.example/b", "B"),
+		"servers/c.yaml": serverYAML("com.example/c", "C"),
+	}
+	dir := writeFiles(t, "", files)
+	reg := newTestRegistry(t, dir, registry.Config{SnapshotRetention: 2})
+
+	first := decode[domain.ServerListResponse](t, get(t, reg, "/v0.1/servers?limit=1"))
+	next := first.Metadata.NextCursor
+	if next == "" {
+		t.Fatal("first page has no next cursor")
+	}
+
+	t.Run("malformed", func(t *testing.T) {
+		resp := problem(t, get(t, reg, "/v0.1/servers?cursor=!!!"), http.StatusBadRequest)
+		if len(resp.Errors) != 1 || resp.Errors[0].Location != "query.cursor" {
+			t.Errorf("errors = %+v, want the cursor parameter named", resp.Errors)
+		}
+	})
+	t.Run("other query", func(t *testing.T) {
+		problem(t, get(t, reg, "/v0.1/servers?search=a&cursor="+url.QueryEscape(next)), http.StatusBadRequest)
+	})
+
+	// Removing b after the first page does not skip or repeat servers
+	// while the snapshot the cursor pins is retained
+	writeFiles(t, dir, map[string]string{"index.yaml": `servers:
+  - name: com.example/a
+    path: servers/a.yaml
+  - name: com.example/c
+    path: servers/c.yaml
+`})
+	resync(t, reg)
+	t.Run("retained", func(t *testing.T) {
+		rec := get(t, reg, "/v0.1/servers?limit=1&cursor="+url.QueryEscape(next))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+		}
+		page := decode[domain.ServerListResponse](t, rec)
+		if len(page.Servers) != 1 || page.Servers[0].Server.Name != "com.example/b" {
+			t.Errorf("second page = %+v, want com.example/b from the pinned snapshot", page.Servers)
+		}
+	})
+
+	writeFiles(t, dir, map[string]string{"servers/c.yaml": serverYAML("com.example/c", "C, again")})
+	resync(t, reg)
+	t.Run("expired", func(t *testing.T) {
+		resp := problem(t, get(t, reg, "/v0.1/servers?limit=1&cursor="+url.QueryEscape(next)), http.StatusGone)
+		if len(resp.Errors) != 1 || resp.Errors[0].Location != "query.cursor" {
+			t.Errorf("errors = %+v, want the cursor parameter named", resp.Errors)
+		}
+	})
+}
+
+func TestIndexUnavailable(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{mode: registry.IndexModeFile, want: "Index not available. Ensure index.yaml exists and is valid."},
+		{mode: registry.IndexModeScan, want: "Index not available. Ensure servers/ contains valid server files."},
+		{mode: registry.IndexModeCompare, want: "Index not available. Ensure servers/ contains valid server files."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			reg := newUnloadedRegistry(t, t.TempDir(), registry.Config{IndexMode: tt.mode})
+			for _, target := range []string{"/v0.1/servers", "/v0.1/facets", "/v0.1/validation"} {
+				resp := problem(t, get(t, reg, target), http.StatusServiceUnavailable)
+				if resp.Detail != tt.want {
+					t.Errorf("%s detail = %q, want %q", target, resp.Detail, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// convertedServer is a server response entry whose server is re-encoded in
+// another schema revision
+type convertedServer struct {
+	Server json.RawMessage `json:"server"`
+	Meta   json.RawMessage `json:"_meta,omitempty"`
+}
+
+type convertedList struct {
+	Servers  []convertedServer `json:"servers"`
+	Metadata json.RawMessage   `json:"metadata"`
+}
+
+// schemaRevision resolves the server.json revision a request wants servers
+// rendered in: the schema query parameter, as a date or schema URL, or
+// else a profile of the Accept header naming a known schema URL. Unknown
+// Accept profiles fall back to the current revision.
+func schemaRevision(r *http.Request) (string, *domain.ErrorDetail) {
+	if v := r.URL.Query().Get("schema"); v != "" {
+		date := v
+		if d := domain.SchemaDate(v); d != "" {
+			date = d
+		}
+		if !domain.KnownRevision(date) {
+			return "", &domain.ErrorDetail{
+				Message:  "must be one of: " + strings.Join(domain.SchemaRevisions(), ", "),
+				Location: "query.schema",
+				Value:    v,
+			}
+		}
+		return date, nil
+	}
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(accepted)
+		if err != nil {
+			continue
+		}
+		// A profile may list several URIs
+		for _, profile := range strings.Fields(params["profile"]) {
+			if date := domain.SchemaDate(profile); domain.KnownRevision(date) {
+				return date, nil
+			}
+		}
+	}
+	return domain.CurrentSchema, nil
+}
+
+// writeServerJSON writes an encoded server or server list response in a
+// schema revision. Servers are held and pre-serialized in the current
+// revision, so other revisions are converted per request.
+func (h *Handlers) writeServerJSON(w http.ResponseWriter, body []byte, revision string, list bool) {
+	w.Header().Add("Vary", "Accept")
+	if revision == domain.CurrentSchema {
+		writeRawJSON(w, http.StatusOK, body)
+		return
+	}
+
+	converted, err := convertServers(body, revision, list)
+	if err != nil {
+		h.logger.Error("failed to convert servers", "schema", revision, "error", err)
+		writeError(w, http.StatusInternalServerError, "Internal Server Error",
+			"Servers could not be converted to schema "+revision)
+		return
+	}
+
+	w.Header().Set("Content-Type", `application/json; profile="`+domain.SchemaURL(revision)+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(converted)
+}
+
+// convertServers re-encodes every server of a response in a schema
+// revision, leaving _meta and list metadata as they are
+func convertServers(body []byte, revision string, list bool) ([]byte, error) {
+	var v any
+	if list {
+		var resp convertedList
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, err
+		}
+		for i := range resp.Servers {
+			if err := convertServer(&resp.Servers[i], revision); err != nil {
+				return nil, err
+			}
+		}
+		v = resp
+	} else {
+		var resp convertedServer
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, err
+		}
+		if err := convertServer(&resp, revision); err != nil {
+			return nil, err
+		}
+		v = resp
+	}
+
+	converted, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(converted, '\n'), nil
+}
+
+func convertServer(entry *convertedServer, revision string) error {
+	var server domain.ServerJSON
+	if err := json.Unmarshal(entry.Server, &server); err != nil {
+		return err
+	}
+	doc, err := domain.DowngradeServer(&server, revision)
+	if err != nil {
+		return err
+	}
+	entry.Server, err = json.Marshal(doc)
+	return err
+}
@@ -17,6 +17,7 @@ type Config struct {
 	Registry      *registry.Registry
 	SyncManager   *sync.Manager
 	WebhookSecret string
+	WebhookRef    string // git ref pattern that triggers a sync
 	Logger        *slog.Logger
 }
 
@@ -31,18 +32,20 @@ func NewRouter(cfg Config) http.Handler {
 
 	// Create handlers
 	handlers := NewHandlers(cfg.Registry, cfg.Logger)
-	webhookHandler := sync.NewWebhookHandler(
-		cfg.WebhookSecret,
-		cfg.SyncManager,
-		cfg.Registry.Store().Branch(),
-		cfg.Logger,
-	)
 
 	// Health and utility endpoints (no version prefix)
 	r.Get("/metrics", promhttp.Handler().ServeHTTP)
 
-	// Webhook endpoint
-	r.Post("/webhooks/github", webhookHandler.ServeHTTP)
+	// Webhook endpoint (only sources that track a moving remote ref)
+	if cfg.WebhookSecret != "" && cfg.WebhookRef != "" {
+		webhookHandler := sync.NewWebhookHandler(
+			cfg.WebhookSecret,
+			cfg.SyncManager,
+			cfg.WebhookRef,
+			cfg.Logger,
+		)
+		r.Post("/webhooks/github", webhookHandler.ServeHTTP)
+	}
 
 	// API v0.1 routes
 	r.Route("/v0.1", func(r chi.Router) {
@@ -50,9 +53,20 @@ func NewRouter(cfg Config) http.Handler {
 		r.Get("/health", handlers.Health)
 		r.Get("/ping", handlers.Ping)
 		r.Get("/version", handlers.Version)
+		r.Get("/validation", handlers.Validation)
+		r.Get("/syncs", handlers.Syncs)
 
 		// Server listing
 		r.Get("/servers", handlers.ListServers)
+		r.Get("/facets", handlers.Facets)
+		r.Get("/changes", handlers.Changes)
+		if cfg.SyncManager != nil {
+			r.Get("/events", sync.NewEventStreamHandler(
+				cfg.SyncManager.Events(),
+				cfg.Registry.Revision,
+				cfg.Logger,
+			).ServeHTTP)
+		}
 
 		// Server details - supports both formats
 		r.Get("/servers/{serverName}", handlers.GetServer)
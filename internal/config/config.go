@@ -7,28 +7,102 @@ import (
 	"time"
 )
 
+// Source backend types
+const (
+	SourceGit   = "git"
+	SourceLocal = "local"
+)
+
+// Git authentication modes
+const (
+	AuthGitHubApp = "github-app"
+	AuthToken     = "token"
+	AuthSSH       = "ssh"
+	AuthNone      = "none"
+)
+
+// Git storage modes
+const (
+	StorageDisk   = "disk"
+	StorageMemory = "memory"
+)
+
 // Config holds all application configuration
 type Config struct {
+	// Source backend: "git" or "local"
+	Source string
+
 	// Registry repository settings
 	RegistryRepoURL string
 	RegistryBranch  string
 
+	// Serve the newest tag matching a glob, or pin a single commit,
+	// instead of tracking the branch head (mutually exclusive). Tags order
+	// by semver when named as versions, else by tag time.
+	RegistryTagPattern string
+	RegistryCommit     string
+
+	// Local directory settings (local source only)
+	LocalPath         string
+	LocalPollInterval time.Duration
+
+	// Git authentication mode: "github-app", "token", "ssh" or "none"
+	GitAuth string
+
 	// GitHub App authentication
 	GitHubAppID          int64
 	GitHubAppPrivateKey  []byte
 	GitHubInstallationID int64
 
+	// Static token authentication
+	GitToken         string
+	GitTokenUsername string
+
+	// SSH deploy key authentication
+	SSHUser           string
+	SSHPrivateKey     []byte
+	SSHKeyPassphrase  string
+	SSHKnownHostsPath string
+
+	// Commit signature verification (enabled when either is set)
+	SigningKeyringPath string
+	AllowedSignersPath string
+
 	// Webhook settings
 	WebhookSecret string
 
-	// Sync settings
-	PollInterval time.Duration
-	CloneTimeout time.Duration
+	// Sync settings. StaleRetryInterval is how often a source serving a
+	// stale commit retries upstream between polls.
+	PollInterval       time.Duration
+	StaleRetryInterval time.Duration
+	CloneTimeout       time.Duration
+
+	// EventHistory is how many applied commits the event stream keeps for
+	// clients resuming with Last-Event-ID
+	EventHistory int
+
+	// Index settings: IndexMode is "file", "scan" or "compare"; ScanDir is
+	// where server files are discovered in the scan modes
+	IndexMode string
+	ScanDir   string
 
-	// Storage settings
-	DataPath  string
+	// Validation policy: "lenient" flags invalid servers, "strict" excludes them
+	ValidationPolicy string
+
+	// Storage settings. GitStorage "memory" keeps the clone in memory and
+	// needs no writable DataPath.
+	GitStorage string
+	DataPath   string
+
+	// Cache settings: CacheMode "preload" holds every server in memory,
+	// "lazy" loads servers on demand into an LRU of CacheSize entries
+	CacheMode string
 	CacheSize int
 
+	// SnapshotRetention is how many snapshots pagination cursors can
+	// keep reading after a sync replaces them, the served one included
+	SnapshotRetention int
+
 	// Server settings
 	Port int
 
@@ -40,18 +114,152 @@ type Config struct {
 func Load() (*Config, error) {
 	cfg := &Config{
 		// Defaults
-		RegistryBranch: "main",
-		PollInterval:   5 * time.Minute,
-		CloneTimeout:   2 * time.Minute,
-		DataPath:       "/data",
-		CacheSize:      1000,
-		Port:           8080,
+		Source:             SourceGit,
+		GitAuth:            AuthGitHubApp,
+		RegistryBranch:     "main",
+		GitStorage:         StorageDisk,
+		IndexMode:          "file",
+		ScanDir:            "servers",
+		ValidationPolicy:   "lenient",
+		LocalPollInterval:  2 * time.Second,
+		PollInterval:       5 * time.Minute,
+		StaleRetryInterval: 30 * time.Second,
+		CloneTimeout:       2 * time.Minute,
+		EventHistory:       100,
+		DataPath:           "/data",
+		CacheMode:          "preload",
+		CacheSize:          1000,
+		SnapshotRetention:  3,
+		Port:               8080,
+	}
+
+	// Optional: Source backend
+	if v := os.Getenv("REGISTRY_SOURCE"); v != "" {
+		cfg.Source = v
+	}
+
+	switch cfg.Source {
+	case SourceGit:
+		if err := loadGitConfig(cfg); err != nil {
+			return nil, err
+		}
+	case SourceLocal:
+		if err := loadLocalConfig(cfg); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid REGISTRY_SOURCE %q: must be %q or %q", cfg.Source, SourceGit, SourceLocal)
+	}
+
+	// Optional: Poll interval
+	if v := os.Getenv("POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid POLL_INTERVAL: %w", err)
+		}
+		cfg.PollInterval = d
+	}
+
+	// Optional: Stale retry interval
+	if v := os.Getenv("STALE_RETRY_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid STALE_RETRY_INTERVAL %q: must be a positive duration", v)
+		}
+		cfg.StaleRetryInterval = d
+	}
+
+	// Optional: Clone timeout
+	if v := os.Getenv("CLONE_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLONE_TIMEOUT: %w", err)
+		}
+		cfg.CloneTimeout = d
+	}
+
+	// Optional: Data path
+	if v := os.Getenv("DATA_PATH"); v != "" {
+		cfg.DataPath = v
+	}
+
+	// Optional: Cache size
+	if v := os.Getenv("CACHE_SIZE"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CACHE_SIZE: %w", err)
+		}
+		cfg.CacheSize = size
+	}
+
+	// Optional: Cache mode
+	if v := os.Getenv("CACHE_MODE"); v != "" {
+		cfg.CacheMode = v
+	}
+	if cfg.CacheMode != "preload" && cfg.CacheMode != "lazy" {
+		return nil, fmt.Errorf("invalid CACHE_MODE %q: must be \"preload\" or \"lazy\"", cfg.CacheMode)
+	}
+
+	// Optional: Snapshot retention
+	if v := os.Getenv("SNAPSHOT_RETENTION"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid SNAPSHOT_RETENTION %q: must be a positive integer", v)
+		}
+		cfg.SnapshotRetention = n
+	}
+
+	// Optional: Event history
+	if v := os.Getenv("EVENT_HISTORY"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid EVENT_HISTORY %q: must be a positive integer", v)
+		}
+		cfg.EventHistory = n
+	}
+
+	// Optional: Port
+	if v := os.Getenv("PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PORT: %w", err)
+		}
+		cfg.Port = port
+	}
+
+	// Optional: Index mode and scan directory
+	if v := os.Getenv("INDEX_MODE"); v != "" {
+		cfg.IndexMode = v
+	}
+	switch cfg.IndexMode {
+	case "file", "scan", "compare":
+	default:
+		return nil, fmt.Errorf("invalid INDEX_MODE %q: must be \"file\", \"scan\" or \"compare\"", cfg.IndexMode)
+	}
+	if v := os.Getenv("SCAN_DIR"); v != "" {
+		cfg.ScanDir = v
+	}
+
+	// Optional: Validation policy
+	if v := os.Getenv("VALIDATION_POLICY"); v != "" {
+		cfg.ValidationPolicy = v
+	}
+	if cfg.ValidationPolicy != "lenient" && cfg.ValidationPolicy != "strict" {
+		return nil, fmt.Errorf("invalid VALIDATION_POLICY %q: must be \"lenient\" or \"strict\"", cfg.ValidationPolicy)
 	}
 
+	// Optional: OTLP endpoint for tracing
+	cfg.OTLPEndpoint = os.Getenv("OTLP_ENDPOINT")
+
+	return cfg, nil
+}
+
+// loadGitConfig reads the settings required to track a remote git repository
+func loadGitConfig(cfg *Config) error {
 	// Required: Registry repo URL
 	cfg.RegistryRepoURL = os.Getenv("REGISTRY_REPO_URL")
 	if cfg.RegistryRepoURL == "" {
-		return nil, fmt.Errorf("REGISTRY_REPO_URL is required")
+		return fmt.Errorf("REGISTRY_REPO_URL is required")
 	}
 
 	// Optional: Branch
@@ -59,14 +267,75 @@ func Load() (*Config, error) {
 		cfg.RegistryBranch = v
 	}
 
+	// Optional: Track a tag pattern or pin a commit
+	cfg.RegistryTagPattern = os.Getenv("REGISTRY_TAG_PATTERN")
+	cfg.RegistryCommit = os.Getenv("REGISTRY_COMMIT")
+	if cfg.RegistryTagPattern != "" && cfg.RegistryCommit != "" {
+		return fmt.Errorf("REGISTRY_TAG_PATTERN and REGISTRY_COMMIT are mutually exclusive")
+	}
+
+	// Optional: Clone storage
+	if v := os.Getenv("GIT_STORAGE"); v != "" {
+		cfg.GitStorage = v
+	}
+	if cfg.GitStorage != StorageDisk && cfg.GitStorage != StorageMemory {
+		return fmt.Errorf("invalid GIT_STORAGE %q: must be %q or %q", cfg.GitStorage, StorageDisk, StorageMemory)
+	}
+
+	// Optional: Auth mode
+	if v := os.Getenv("GIT_AUTH"); v != "" {
+		cfg.GitAuth = v
+	}
+
+	switch cfg.GitAuth {
+	case AuthGitHubApp:
+		if err := loadGitHubAppConfig(cfg); err != nil {
+			return err
+		}
+	case AuthToken:
+		cfg.GitToken = os.Getenv("GIT_TOKEN")
+		if cfg.GitToken == "" {
+			return fmt.Errorf("GIT_TOKEN is required when GIT_AUTH=token")
+		}
+		cfg.GitTokenUsername = os.Getenv("GIT_TOKEN_USERNAME")
+	case AuthSSH:
+		if err := loadSSHConfig(cfg); err != nil {
+			return err
+		}
+	case AuthNone:
+	default:
+		return fmt.Errorf("invalid GIT_AUTH %q: must be one of %q, %q, %q or %q",
+			cfg.GitAuth, AuthGitHubApp, AuthToken, AuthSSH, AuthNone)
+	}
+
+	// Optional: Commit signature verification
+	cfg.SigningKeyringPath = os.Getenv("GIT_SIGNING_KEYRING_PATH")
+	cfg.AllowedSignersPath = os.Getenv("GIT_ALLOWED_SIGNERS_PATH")
+
+	// Required for GitHub App installs tracking a moving ref: Webhook secret
+	cfg.WebhookSecret = os.Getenv("WEBHOOK_SECRET")
+	if cfg.WebhookSecret == "" && cfg.GitAuth == AuthGitHubApp && cfg.RegistryCommit == "" {
+		return fmt.Errorf("WEBHOOK_SECRET is required")
+	}
+
+	return nil
+}
+
+// VerifySignatures reports whether commit signatures must be checked
+func (c *Config) VerifySignatures() bool {
+	return c.SigningKeyringPath != "" || c.AllowedSignersPath != ""
+}
+
+// loadGitHubAppConfig reads GitHub App credentials
+func loadGitHubAppConfig(cfg *Config) error {
 	// Required: GitHub App credentials
 	appIDStr := os.Getenv("GITHUB_APP_ID")
 	if appIDStr == "" {
-		return nil, fmt.Errorf("GITHUB_APP_ID is required")
+		return fmt.Errorf("GITHUB_APP_ID is required")
 	}
 	appID, err := strconv.ParseInt(appIDStr, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid GITHUB_APP_ID: %w", err)
+		return fmt.Errorf("invalid GITHUB_APP_ID: %w", err)
 	}
 	cfg.GitHubAppID = appID
 
@@ -76,74 +345,68 @@ func Load() (*Config, error) {
 	if privateKeyPath != "" {
 		key, err := os.ReadFile(privateKeyPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read private key file: %w", err)
+			return fmt.Errorf("failed to read private key file: %w", err)
 		}
 		cfg.GitHubAppPrivateKey = key
 	} else if privateKeyValue != "" {
 		cfg.GitHubAppPrivateKey = []byte(privateKeyValue)
 	} else {
-		return nil, fmt.Errorf("GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_PATH is required")
+		return fmt.Errorf("GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_PATH is required")
 	}
 
 	installIDStr := os.Getenv("GITHUB_INSTALLATION_ID")
 	if installIDStr == "" {
-		return nil, fmt.Errorf("GITHUB_INSTALLATION_ID is required")
+		return fmt.Errorf("GITHUB_INSTALLATION_ID is required")
 	}
 	installID, err := strconv.ParseInt(installIDStr, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid GITHUB_INSTALLATION_ID: %w", err)
+		return fmt.Errorf("invalid GITHUB_INSTALLATION_ID: %w", err)
 	}
 	cfg.GitHubInstallationID = installID
 
-	// Required: Webhook secret
-	cfg.WebhookSecret = os.Getenv("WEBHOOK_SECRET")
-	if cfg.WebhookSecret == "" {
-		return nil, fmt.Errorf("WEBHOOK_SECRET is required")
-	}
+	return nil
+}
 
-	// Optional: Poll interval
-	if v := os.Getenv("POLL_INTERVAL"); v != "" {
-		d, err := time.ParseDuration(v)
+// loadSSHConfig reads the SSH deploy key settings
+func loadSSHConfig(cfg *Config) error {
+	// Key can be provided as file path or direct value
+	keyPath := os.Getenv("GIT_SSH_KEY_PATH")
+	keyValue := os.Getenv("GIT_SSH_KEY")
+	if keyPath != "" {
+		key, err := os.ReadFile(keyPath)
 		if err != nil {
-			return nil, fmt.Errorf("invalid POLL_INTERVAL: %w", err)
+			return fmt.Errorf("failed to read SSH key file: %w", err)
 		}
-		cfg.PollInterval = d
+		cfg.SSHPrivateKey = key
+	} else if keyValue != "" {
+		cfg.SSHPrivateKey = []byte(keyValue)
+	} else {
+		return fmt.Errorf("GIT_SSH_KEY or GIT_SSH_KEY_PATH is required when GIT_AUTH=ssh")
 	}
 
-	// Optional: Clone timeout
-	if v := os.Getenv("CLONE_TIMEOUT"); v != "" {
-		d, err := time.ParseDuration(v)
-		if err != nil {
-			return nil, fmt.Errorf("invalid CLONE_TIMEOUT: %w", err)
-		}
-		cfg.CloneTimeout = d
-	}
+	cfg.SSHUser = os.Getenv("GIT_SSH_USER")
+	cfg.SSHKeyPassphrase = os.Getenv("GIT_SSH_KEY_PASSPHRASE")
+	cfg.SSHKnownHostsPath = os.Getenv("GIT_SSH_KNOWN_HOSTS_PATH")
 
-	// Optional: Data path
-	if v := os.Getenv("DATA_PATH"); v != "" {
-		cfg.DataPath = v
-	}
+	return nil
+}
 
-	// Optional: Cache size
-	if v := os.Getenv("CACHE_SIZE"); v != "" {
-		size, err := strconv.Atoi(v)
-		if err != nil {
-			return nil, fmt.Errorf("invalid CACHE_SIZE: %w", err)
-		}
-		cfg.CacheSize = size
+// loadLocalConfig reads the settings for serving a local directory
+func loadLocalConfig(cfg *Config) error {
+	// Required: Local registry directory
+	cfg.LocalPath = os.Getenv("REGISTRY_LOCAL_PATH")
+	if cfg.LocalPath == "" {
+		return fmt.Errorf("REGISTRY_LOCAL_PATH is required when REGISTRY_SOURCE=local")
 	}
 
-	// Optional: Port
-	if v := os.Getenv("PORT"); v != "" {
-		port, err := strconv.Atoi(v)
+	// Optional: Directory polling interval
+	if v := os.Getenv("LOCAL_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
 		if err != nil {
-			return nil, fmt.Errorf("invalid PORT: %w", err)
+			return fmt.Errorf("invalid LOCAL_POLL_INTERVAL: %w", err)
 		}
-		cfg.Port = port
+		cfg.LocalPollInterval = d
 	}
 
-	// Optional: OTLP endpoint for tracing
-	cfg.OTLPEndpoint = os.Getenv("OTLP_ENDPOINT")
-
-	return cfg, nil
+	return nil
 }
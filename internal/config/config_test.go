@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadSyncSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantRetry   time.Duration
+		wantHistory int
+		wantErr     bool
+	}{
+		{
+			name:        "defaults",
+			wantRetry:   30 * time.Second,
+			wantHistory: 100,
+		},
+		{
+			name:        "overrides",
+			env:         map[string]string{"STALE_RETRY_INTERVAL": "5s", "EVENT_HISTORY": "500"},
+			wantRetry:   5 * time.Second,
+			wantHistory: 500,
+		},
+		{
+			name:    "unparseable retry interval",
+			env:     map[string]string{"STALE_RETRY_INTERVAL": "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "zero retry interval",
+			env:     map[string]string{"STALE_RETRY_INTERVAL": "0s"},
+			wantErr: true,
+		},
+		{
+			name:    "zero event history",
+			env:     map[string]string{"EVENT_HISTORY": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "unparseable event history",
+			env:     map[string]string{"EVENT_HISTORY": "many"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("REGISTRY_SOURCE", "git")
+			t.Setenv("REGISTRY_REPO_URL", "https://github.com/example/registry.git")
+			t.Setenv("GIT_AUTH", "none")
+			t.Setenv("STALE_RETRY_INTERVAL", "")
+			t.Setenv("EVENT_HISTORY", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Load() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.StaleRetryInterval != tt.wantRetry {
+				t.Errorf("StaleRetryInterval = %v, want %v", cfg.StaleRetryInterval, tt.wantRetry)
+			}
+			if cfg.EventHistory != tt.wantHistory {
+				t.Errorf("EventHistory = %d, want %d", cfg.EventHistory, tt.wantHistory)
+			}
+		})
+	}
+}
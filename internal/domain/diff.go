@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Change types
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// ChangesResponse lists how servers changed between two commits
+type ChangesResponse struct {
+	From    string         `json:"from"` // empty when the history starts after since
+	To      string         `json:"to"`
+	Changes []ServerChange `json:"changes"`
+}
+
+// ServerChange is a server added, removed or modified between two commits
+type ServerChange struct {
+	Name      string        `json:"name"`
+	Type      string        `json:"type"`
+	Version   string        `json:"version,omitempty"`
+	Commit    string        `json:"commit,omitempty"` // last commit that changed it
+	UpdatedAt time.Time     `json:"updatedAt,omitzero"`
+	Fields    []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange is one field of a server definition that differs between
+// two versions. Path uses JSON field names, e.g. packages[0].version.
+type FieldChange struct {
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	Old         any    `json:"old,omitempty"`
+	New         any    `json:"new,omitempty"`
+	Description string `json:"description"`
+}
+
+// DiffServers lists the fields that differ between two versions of a
+// server. List elements are compared by position.
+func DiffServers(old, new *ServerJSON) ([]FieldChange, error) {
+	a, err := toJSONValue(old)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toJSONValue(new)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	diffValues(&changes, "", a, b)
+	for i := range changes {
+		changes[i].Description = describeChange(changes[i])
+	}
+	return changes, nil
+}
+
+func toJSONValue(server *ServerJSON) (any, error) {
+	data, err := json.Marshal(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", server.Name, err)
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", server.Name, err)
+	}
+	return v, nil
+}
+
+func diffValues(changes *[]FieldChange, path string, a, b any) {
+	switch av := a.(type) {
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			keys := make([]string, 0, len(av)+len(bv))
+			for k := range av {
+				keys = append(keys, k)
+			}
+			for k := range bv {
+				if _, ok := av[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				diffMember(changes, joinPath(path, k), av, bv, k)
+			}
+			return
+		}
+
+	case []any:
+		if bv, ok := b.([]any); ok {
+			for i := range max(len(av), len(bv)) {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(bv):
+					*changes = append(*changes, FieldChange{Path: p, Type: ChangeRemoved, Old: av[i]})
+				case i >= len(av):
+					*changes = append(*changes, FieldChange{Path: p, Type: ChangeAdded, New: bv[i]})
+				default:
+					diffValues(changes, p, av[i], bv[i])
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, FieldChange{Path: path, Type: ChangeModified, Old: a, New: b})
+	}
+}
+
+func diffMember(changes *[]FieldChange, path string, a, b map[string]any, key string) {
+	av, inA := a[key]
+	bv, inB := b[key]
+	// Empty lists are omitted, so a list that appears or disappears is
+	// compared with an empty one, element by element
+	switch {
+	case !inB:
+		if _, ok := av.([]any); ok {
+			diffValues(changes, path, av, []any{})
+			return
+		}
+		*changes = append(*changes, FieldChange{Path: path, Type: ChangeRemoved, Old: av})
+	case !inA:
+		if _, ok := bv.([]any); ok {
+			diffValues(changes, path, []any{}, bv)
+			return
+		}
+		*changes = append(*changes, FieldChange{Path: path, Type: ChangeAdded, New: bv})
+	default:
+		diffValues(changes, path, av, bv)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+var listIndex = regexp.MustCompile(`\[\d+\]`)
+
+// describeChange summarizes a field change for people reading a changelog
+func describeChange(c FieldChange) string {
+	field := func(v any, key string) string {
+		m, _ := v.(map[string]any)
+		s, _ := m[key].(string)
+		return s
+	}
+	element := c.New
+	if c.Type == ChangeRemoved {
+		element = c.Old
+	}
+
+	switch pattern := listIndex.ReplaceAllString(c.Path, "[]"); {
+	case pattern == "version" && c.Type == ChangeModified:
+		return fmt.Sprintf("version changed from %v to %v", c.Old, c.New)
+
+	case pattern == "remotes[]":
+		return fmt.Sprintf("remote %s %s", field(element, "url"), c.Type)
+	case pattern == "remotes[].url":
+		return "remote URL changed"
+	case pattern == "remotes[].type":
+		return fmt.Sprintf("remote transport changed from %v to %v", c.Old, c.New)
+
+	case pattern == "packages[]":
+		return fmt.Sprintf("package %s %s", field(element, "identifier"), c.Type)
+	case pattern == "packages[].version" && c.Type == ChangeModified:
+		return fmt.Sprintf("package version changed from %v to %v", c.Old, c.New)
+	case pattern == "packages[].transport.type":
+		return fmt.Sprintf("package transport changed from %v to %v", c.Old, c.New)
+
+	case pattern == "packages[].environmentVariables[]":
+		name := field(element, "name")
+		if c.Type == ChangeAdded {
+			if required, _ := element.(map[string]any)["isRequired"].(bool); required {
+				return "new required env var " + name
+			}
+			return "new env var " + name
+		}
+		return fmt.Sprintf("env var %s %s", name, c.Type)
+	case pattern == "packages[].environmentVariables[].isRequired":
+		if c.New == true {
+			return "env var is now required"
+		}
+		return "env var is no longer required"
+
+	case strings.HasPrefix(pattern, "packages[].environmentVariables[]."):
+		return fmt.Sprintf("env var %s %s", strings.TrimPrefix(pattern, "packages[].environmentVariables[]."), c.Type)
+	}
+
+	return fmt.Sprintf("%s %s", c.Path, c.Type)
+}
@@ -15,4 +15,5 @@ type IndexEntry struct {
 	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
 	Version     string            `json:"version,omitempty" yaml:"version,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Status      *ServerStatus     `json:"status,omitempty" yaml:"status,omitempty"`
 }
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // ServerResponse wraps a server with metadata for API responses
 type ServerResponse struct {
 	Server ServerJSON  `json:"server"`
@@ -18,23 +20,123 @@ type ListMetadata struct {
 	Count      int    `json:"count"`
 }
 
+// FacetsResponse counts servers per facet value, e.g.
+// Facets["transport"]["stdio"], for building filter UIs
+type FacetsResponse struct {
+	Revision string                    `json:"revision"`
+	Total    int                       `json:"total"`
+	Facets   map[string]map[string]int `json:"facets"`
+}
+
+// ServerVersionsResponse lists every published version of a server
+type ServerVersionsResponse struct {
+	ServerName string        `json:"server_name"`
+	Versions   []VersionInfo `json:"versions"`
+}
+
+// VersionInfo describes a single published version of a server.
+// PublishedAt is when the version first appeared; Commit is the most
+// recent commit carrying that version.
+type VersionInfo struct {
+	Version     string    `json:"version"`
+	PublishedAt time.Time `json:"published_at"`
+	Commit      string    `json:"commit"`
+	IsLatest    bool      `json:"is_latest"`
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status       string      `json:"status"`
-	RepoURL      string      `json:"repo_url"`
-	Branch       string      `json:"branch"`
-	CommitSHA    string      `json:"commit_sha"`
-	LastSyncAt   string      `json:"last_sync_at"`
-	IndexStatus  string      `json:"index_status"`
-	ServerCount  int         `json:"server_count"`
-	CacheStats   *CacheStats `json:"cache_stats,omitempty"`
+	Status        string             `json:"status"`
+	Source        string             `json:"source"`
+	RepoURL       string             `json:"repo_url"`
+	Branch        string             `json:"branch"`
+	TagPattern    string             `json:"tag_pattern,omitempty"`
+	Tag           string             `json:"tag,omitempty"`
+	PinnedCommit  string             `json:"pinned_commit,omitempty"`
+	CommitSHA     string             `json:"commit_sha"`
+	Stale         bool               `json:"stale,omitempty"`
+	StaleReason   string             `json:"stale_reason,omitempty"`
+	LastSyncAt    string             `json:"last_sync_at"`
+	IndexStatus   string             `json:"index_status"`
+	IndexMode     string             `json:"index_mode"`
+	IndexDrift    []string           `json:"index_drift,omitempty"`
+	ServerCount   int                `json:"server_count"`
+	CacheStats    *CacheStats        `json:"cache_stats,omitempty"`
+	LastRejection *SyncRejection     `json:"last_rejection,omitempty"`
+	Quarantined   []QuarantinedEntry `json:"quarantined,omitempty"`
+	Validation    *ValidationSummary `json:"validation,omitempty"`
+	Signature     *CommitSignature   `json:"signature,omitempty"`
+}
+
+// QuarantinedEntry is an index entry that failed integrity checks and is
+// not served
+type QuarantinedEntry struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// CommitSignature reports the most recent commit signature check
+type CommitSignature struct {
+	Commit    string    `json:"commit"`
+	Verified  bool      `json:"verified"`
+	Method    string    `json:"method,omitempty"`
+	Signer    string    `json:"signer,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// SyncRejection describes a revision that failed validation and was not served
+type SyncRejection struct {
+	Revision   string    `json:"revision"`
+	RejectedAt time.Time `json:"rejected_at"`
+	Errors     []string  `json:"errors"`
+}
+
+// SyncDiff describes what a sync changed between two revisions
+type SyncDiff struct {
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	SyncedAt    time.Time `json:"synced_at"`
+	Paths       []string  `json:"paths"` // files changed between the revisions
+	Added       []string  `json:"added"`
+	Removed     []string  `json:"removed"`
+	Modified    []string  `json:"modified"`
+	Invalidated int       `json:"invalidated"` // cache entries dropped
+}
+
+// SyncEvent announces an applied commit on the event stream. Reset events
+// only carry the commit being served.
+type SyncEvent struct {
+	Commit         string    `json:"commit"`
+	PreviousCommit string    `json:"previous_commit,omitempty"`
+	SyncedAt       time.Time `json:"synced_at,omitzero"`
+	Added          []string  `json:"added,omitempty"`
+	Removed        []string  `json:"removed,omitempty"`
+	Modified       []string  `json:"modified,omitempty"`
+}
+
+// SyncDiffsResponse lists recent sync diffs, newest first
+type SyncDiffsResponse struct {
+	Syncs []SyncDiff `json:"syncs"`
 }
 
 // CacheStats contains cache statistics
 type CacheStats struct {
+	Mode     string  `json:"mode"`
 	Size     int     `json:"size"`
 	Capacity int     `json:"capacity"`
 	HitRate  float64 `json:"hit_rate"`
+
+	// EstimatedMemoryBytes counts pre-serialized responses exactly and
+	// stands in the size of its definition file for each parsed server;
+	// the size of parsed structs is not measured
+	EstimatedMemoryBytes int64 `json:"estimated_memory_bytes"`
+
+	// Misses that waited for a load already in flight, and lookups of
+	// unknown names answered by the negative cache
+	Coalesced    int64 `json:"coalesced"`
+	NegativeHits int64 `json:"negative_hits"`
 }
 
 // PingResponse represents the ping response
@@ -71,3 +173,38 @@ type NotImplementedResponse struct {
 	Detail  string `json:"detail"`
 	SeeAlso string `json:"see_also"`
 }
+
+// ServerValidation is the validation result for one server definition
+type ServerValidation struct {
+	Name     string            `json:"name"`
+	Path     string            `json:"path"`
+	Valid    bool              `json:"valid"`
+	Excluded bool              `json:"excluded,omitempty"`
+	Issues   []ValidationIssue `json:"issues,omitempty"`
+
+	// ConvertedFrom is the $schema of a definition written against an
+	// earlier revision and converted after validation
+	ConvertedFrom string `json:"converted_from,omitempty"`
+}
+
+// ValidationReport lists validation results for every server in a snapshot.
+// Provisional lists the schemas whose issues are only advisory.
+type ValidationReport struct {
+	Revision    string             `json:"revision"`
+	Policy      string             `json:"policy"`
+	Schemas     []string           `json:"schemas"`
+	Provisional []string           `json:"provisional_schemas,omitempty"`
+	CheckedAt   time.Time          `json:"checked_at"`
+	Total       int                `json:"total"`
+	Valid       int                `json:"valid"`
+	Invalid     int                `json:"invalid"`
+	Servers     []ServerValidation `json:"servers"`
+}
+
+// ValidationSummary reports validation counts in the health response
+type ValidationSummary struct {
+	Policy   string `json:"policy"`
+	Valid    int    `json:"valid"`
+	Invalid  int    `json:"invalid"`
+	Excluded int    `json:"excluded"`
+}
@@ -0,0 +1,265 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SchemaBaseURL is the prefix of every official server.json schema URL
+const SchemaBaseURL = "https://static.modelcontextprotocol.io/schemas/"
+
+// CurrentSchema is the server.json revision ServerJSON follows
+const CurrentSchema = "2025-12-11"
+
+// ErrUnknownRevision is returned for schema revisions that cannot be converted
+var ErrUnknownRevision = errors.New("unknown schema revision")
+
+// SchemaURL returns the official schema URL for a revision date
+func SchemaURL(date string) string {
+	return SchemaBaseURL + date + "/server.schema.json"
+}
+
+// SchemaDate returns the revision date of an official schema URL, or ""
+// if the URL is not one
+func SchemaDate(url string) string {
+	date, ok := strings.CutPrefix(url, SchemaBaseURL)
+	if !ok {
+		return ""
+	}
+	date, ok = strings.CutSuffix(date, "/server.schema.json")
+	if !ok || strings.Contains(date, "/") {
+		return ""
+	}
+	return date
+}
+
+// schemaStep converts documents between an earlier revision and the one
+// that followed it
+type schemaStep struct {
+	from string
+	up   func(doc map[string]any)
+	down func(doc map[string]any)
+}
+
+// schemaSteps lists the revisions before CurrentSchema, oldest first. Each
+// step converts to the next revision; the last one to CurrentSchema.
+var schemaSteps = []schemaStep{
+	{from: "2025-07-09", up: upFrom20250709, down: downTo20250709},
+	{from: "2025-09-29", up: upFrom20250929, down: downTo20250929},
+}
+
+// SchemaRevisions returns every revision servers can be read and rendered
+// in, oldest first
+func SchemaRevisions() []string {
+	dates := make([]string, 0, len(schemaSteps)+1)
+	for _, s := range schemaSteps {
+		dates = append(dates, s.from)
+	}
+	return append(dates, CurrentSchema)
+}
+
+// KnownRevision reports whether servers can be converted to and from a
+// revision
+func KnownRevision(date string) bool {
+	return date == CurrentSchema || stepIndex(date) >= 0
+}
+
+func stepIndex(date string) int {
+	for i, s := range schemaSteps {
+		if s.from == date {
+			return i
+		}
+	}
+	return -1
+}
+
+// UpgradeServer converts a decoded server.json document written against an
+// earlier revision to CurrentSchema, in place, and reports whether it did.
+// Documents of the current or an unknown revision are left alone.
+func UpgradeServer(doc map[string]any) bool {
+	url, _ := doc["$schema"].(string)
+	i := stepIndex(SchemaDate(url))
+	if i < 0 {
+		return false
+	}
+	for _, s := range schemaSteps[i:] {
+		s.up(doc)
+	}
+	doc["$schema"] = SchemaURL(CurrentSchema)
+	return true
+}
+
+// DowngradeServer renders a server as a document of an earlier revision.
+// Fields the revision cannot express are dropped.
+func DowngradeServer(server *ServerJSON, date string) (map[string]any, error) {
+	if !KnownRevision(date) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRevision, date)
+	}
+
+	content, err := json.Marshal(server)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+
+	if date != CurrentSchema {
+		for i := len(schemaSteps) - 1; i >= stepIndex(date); i-- {
+			schemaSteps[i].down(doc)
+		}
+	}
+	doc["$schema"] = SchemaURL(date)
+	return doc, nil
+}
+
+// Current names for the snake_case names of 2025-07-09
+var (
+	legacyPackageKeys = map[string]string{
+		"registry_name":         "registryType",
+		"registry_base_url":     "registryBaseUrl",
+		"name":                  "identifier",
+		"runtime_hint":          "runtimeHint",
+		"file_sha256":           "fileSha256",
+		"environment_variables": "environmentVariables",
+		"package_arguments":     "packageArguments",
+		"runtime_arguments":     "runtimeArguments",
+	}
+	legacyInputKeys = map[string]string{
+		"is_required": "isRequired",
+		"is_secret":   "isSecret",
+		"is_repeated": "isRepeated",
+		"value_hint":  "valueHint",
+	}
+	legacyRegistryTypes = map[string]string{
+		"docker": "oci",
+	}
+)
+
+// upFrom20250709 converts snake_case names to camelCase, lifts
+// version_detail.version, renames remote transport_type to type and gives
+// packages, which could only run over stdio, an explicit transport
+func upFrom20250709(doc map[string]any) {
+	if detail, ok := doc["version_detail"].(map[string]any); ok {
+		if _, set := doc["version"]; !set {
+			doc["version"] = detail["version"]
+		}
+		delete(doc, "version_detail")
+	}
+	renameKey(doc, "website_url", "websiteUrl")
+
+	for _, pkg := range objects(doc["packages"]) {
+		renameKeys(pkg, legacyPackageKeys)
+		if t, ok := pkg["registryType"].(string); ok && legacyRegistryTypes[t] != "" {
+			pkg["registryType"] = legacyRegistryTypes[t]
+		}
+		if _, ok := pkg["transport"]; !ok {
+			pkg["transport"] = map[string]any{"type": "stdio"}
+		}
+		for _, key := range []string{"environmentVariables", "packageArguments", "runtimeArguments"} {
+			renameInputs(pkg[key], legacyInputKeys)
+		}
+	}
+
+	for _, remote := range objects(doc["remotes"]) {
+		renameKey(remote, "transport_type", "type")
+		renameInputs(remote["headers"], legacyInputKeys)
+	}
+}
+
+// downTo20250709 reverses upFrom20250709. Package transports are dropped,
+// since the revision assumed stdio.
+func downTo20250709(doc map[string]any) {
+	if version, ok := doc["version"]; ok {
+		doc["version_detail"] = map[string]any{"version": version}
+		delete(doc, "version")
+	}
+	renameKey(doc, "websiteUrl", "website_url")
+
+	packageKeys := invert(legacyPackageKeys)
+	inputKeys := invert(legacyInputKeys)
+	registryTypes := invert(legacyRegistryTypes)
+	for _, pkg := range objects(doc["packages"]) {
+		delete(pkg, "transport")
+		for _, key := range []string{"environmentVariables", "packageArguments", "runtimeArguments"} {
+			renameInputs(pkg[key], inputKeys)
+		}
+		if t, ok := pkg["registryType"].(string); ok && registryTypes[t] != "" {
+			pkg["registryType"] = registryTypes[t]
+		}
+		renameKeys(pkg, packageKeys)
+	}
+
+	for _, remote := range objects(doc["remotes"]) {
+		renameKey(remote, "type", "transport_type")
+		renameInputs(remote["headers"], inputKeys)
+	}
+}
+
+// upFrom20250929 turns the top-level status string into the status block
+// the registry reads lifecycle states from
+func upFrom20250929(doc map[string]any) {
+	if state, ok := doc["status"].(string); ok {
+		doc["status"] = map[string]any{"state": state}
+	}
+}
+
+// downTo20250929 is a no-op: lifecycle states are served under _meta, not
+// as part of the server
+func downTo20250929(doc map[string]any) {}
+
+// renameInputs renames the keys of a list of inputs and of the variables
+// nested in them
+func renameInputs(list any, keys map[string]string) {
+	for _, input := range objects(list) {
+		renameKeys(input, keys)
+		if vars, ok := input["variables"].(map[string]any); ok {
+			for _, v := range vars {
+				if m, ok := v.(map[string]any); ok {
+					renameKeys(m, keys)
+				}
+			}
+		}
+	}
+}
+
+func renameKeys(m map[string]any, keys map[string]string) {
+	for from, to := range keys {
+		renameKey(m, from, to)
+	}
+}
+
+// renameKey moves a value to a new key, unless the new key is already set
+func renameKey(m map[string]any, from, to string) {
+	v, ok := m[from]
+	if !ok {
+		return
+	}
+	delete(m, from)
+	if _, set := m[to]; !set {
+		m[to] = v
+	}
+}
+
+// objects returns the elements of a list that are objects
+func objects(list any) []map[string]any {
+	items, _ := list.([]any)
+	objs := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			objs = append(objs, m)
+		}
+	}
+	return objs
+}
+
+func invert(m map[string]string) map[string]string {
+	inverted := make(map[string]string, len(m))
+	for k, v := range m {
+		inverted[v] = k
+	}
+	return inverted
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const legacySchema = "2025-07-09"
+
+// decode parses a JSON document for comparison with converted documents
+func decode(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(s), &doc); err != nil {
+		t.Fatalf("invalid test document: %v", err)
+	}
+	return doc
+}
+
+func TestSchemaDate(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: SchemaURL(CurrentSchema), want: CurrentSchema},
+		{url: SchemaURL(legacySchema), want: legacySchema},
+		{url: SchemaBaseURL + "2025-07-09/other.schema.json", want: ""},
+		{url: SchemaBaseURL + "draft/2025-07-09/server.schema.json", want: ""},
+		{url: "https://example.com/schemas/2025-07-09/server.schema.json", want: ""},
+		{url: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := SchemaDate(tt.url); got != tt.want {
+				t.Errorf("SchemaDate(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgradeServer(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		want      string
+		converted bool
+	}{
+		{
+			name: "current revision is left alone",
+			doc:  `{"$schema":"` + SchemaURL(CurrentSchema) + `","version":"1.0.0","website_url":"x"}`,
+			want: `{"$schema":"` + SchemaURL(CurrentSchema) + `","version":"1.0.0","website_url":"x"}`,
+		},
+		{
+			name: "unknown revision is left alone",
+			doc:  `{"$schema":"` + SchemaURL("2025-01-01") + `","version_detail":{"version":"1.0.0"}}`,
+			want: `{"$schema":"` + SchemaURL("2025-01-01") + `","version_detail":{"version":"1.0.0"}}`,
+		},
+		{
+			name:      "status string",
+			doc:       `{"$schema":"` + SchemaURL("2025-09-29") + `","version":"1.0.0","status":"deprecated"}`,
+			want:      `{"$schema":"` + SchemaURL(CurrentSchema) + `","version":"1.0.0","status":{"state":"deprecated"}}`,
+			converted: true,
+		},
+		{
+			name:      "status block is left alone",
+			doc:       `{"$schema":"` + SchemaURL("2025-09-29") + `","status":{"state":"deleted"}}`,
+			want:      `{"$schema":"` + SchemaURL(CurrentSchema) + `","status":{"state":"deleted"}}`,
+			converted: true,
+		},
+		{
+			name: "version and website",
+			doc: `{"$schema":"` + SchemaURL(legacySchema) + `",
+				"version_detail":{"version":"1.2.0"},"website_url":"https://example.com"}`,
+			want: `{"$schema":"` + SchemaURL(CurrentSchema) + `",
+				"version":"1.2.0","websiteUrl":"https://example.com"}`,
+			converted: true,
+		},
+		{
+			name:      "set version wins over version_detail",
+			doc:       `{"$schema":"` + SchemaURL(legacySchema) + `","version":"2.0.0","version_detail":{"version":"1.0.0"}}`,
+			want:      `{"$schema":"` + SchemaURL(CurrentSchema) + `","version":"2.0.0"}`,
+			converted: true,
+		},
+		{
+			name: "packages",
+			doc: `{"$schema":"` + SchemaURL(legacySchema) + `","packages":[{
+				"registry_name":"docker","name":"example/server","runtime_hint":"docker",
+				"environment_variables":[{"name":"TOKEN","is_required":true,"is_secret":true}],
+				"package_arguments":[{"type":"named","name":"--port","value_hint":"port",
+					"variables":{"port":{"is_required":true}}}]}]}`,
+			want: `{"$schema":"` + SchemaURL(CurrentSchema) + `","packages":[{
+				"registryType":"oci","identifier":"example/server","runtimeHint":"docker",
+				"transport":{"type":"stdio"},
+				"environmentVariables":[{"name":"TOKEN","isRequired":true,"isSecret":true}],
+				"packageArguments":[{"type":"named","name":"--port","valueHint":"port",
+					"variables":{"port":{"isRequired":true}}}]}]}`,
+			converted: true,
+		},
+		{
+			name: "remotes",
+			doc: `{"$schema":"` + SchemaURL(legacySchema) + `","remotes":[{
+				"transport_type":"sse","url":"https://example.com/sse",
+				"headers":[{"name":"Authorization","is_secret":true}]}]}`,
+			want: `{"$schema":"` + SchemaURL(CurrentSchema) + `","remotes":[{
+				"type":"sse","url":"https://example.com/sse",
+				"headers":[{"name":"Authorization","isSecret":true}]}]}`,
+			converted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := decode(t, tt.doc)
+			if converted := UpgradeServer(doc); converted != tt.converted {
+				t.Errorf("UpgradeServer() = %v, want %v", converted, tt.converted)
+			}
+			if want := decode(t, tt.want); !reflect.DeepEqual(doc, want) {
+				t.Errorf("UpgradeServer() doc = %v, want %v", doc, want)
+			}
+		})
+	}
+}
+
+func TestDowngradeServer(t *testing.T) {
+	server := &ServerJSON{
+		Schema:      SchemaURL(CurrentSchema),
+		Name:        "io.example/server",
+		Description: "Example",
+		Version:     "1.2.0",
+		WebsiteURL:  "https://example.com",
+		Packages: []Package{{
+			RegistryType: "oci",
+			Identifier:   "example/server",
+			RuntimeHint:  "docker",
+			Transport:    Transport{Type: "stdio"},
+			EnvironmentVariables: []EnvironmentVariable{
+				{Name: "TOKEN", IsRequired: true, IsSecret: true},
+			},
+		}},
+		Remotes: []Remote{{
+			Type:    "sse",
+			URL:     "https://example.com/sse",
+			Headers: []KeyValueInput{{Name: "Authorization", IsSecret: true}},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		date    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "current revision",
+			date: CurrentSchema,
+			want: `{"$schema":"` + SchemaURL(CurrentSchema) + `","name":"io.example/server",
+				"description":"Example","version":"1.2.0","websiteUrl":"https://example.com",
+				"packages":[{"registryType":"oci","identifier":"example/server","runtimeHint":"docker",
+					"transport":{"type":"stdio"},
+					"environmentVariables":[{"name":"TOKEN","isRequired":true,"isSecret":true}]}],
+				"remotes":[{"type":"sse","url":"https://example.com/sse",
+					"headers":[{"name":"Authorization","isSecret":true}]}]}`,
+		},
+		{
+			name: "legacy revision",
+			date: legacySchema,
+			want: `{"$schema":"` + SchemaURL(legacySchema) + `","name":"io.example/server",
+				"description":"Example","version_detail":{"version":"1.2.0"},"website_url":"https://example.com",
+				"packages":[{"registry_name":"docker","name":"example/server","runtime_hint":"docker",
+					"environment_variables":[{"name":"TOKEN","is_required":true,"is_secret":true}]}],
+				"remotes":[{"transport_type":"sse","url":"https://example.com/sse",
+					"headers":[{"name":"Authorization","is_secret":true}]}]}`,
+		},
+		{
+			name:    "unknown revision",
+			date:    "2025-01-01",
+			wantErr: ErrUnknownRevision,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := DowngradeServer(server, tt.date)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DowngradeServer(%s) error = %v, want %v", tt.date, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DowngradeServer(%s) error = %v", tt.date, err)
+			}
+			if want := decode(t, tt.want); !reflect.DeepEqual(doc, want) {
+				t.Errorf("DowngradeServer(%s) = %v, want %v", tt.date, doc, want)
+			}
+		})
+	}
+}
+
+func TestSchemaStepsRoundTrip(t *testing.T) {
+	for _, date := range SchemaRevisions() {
+		t.Run(date, func(t *testing.T) {
+			server := &ServerJSON{
+				Schema:      SchemaURL(CurrentSchema),
+				Name:        "io.example/server",
+				Description: "Example",
+				Version:     "1.0.0",
+				Packages: []Package{{
+					RegistryType: "npm",
+					Identifier:   "@example/server",
+					Transport:    Transport{Type: "stdio"},
+				}},
+				Remotes: []Remote{{Type: "streamable-http", URL: "https://example.com/mcp"}},
+			}
+			doc, err := DowngradeServer(server, date)
+			if err != nil {
+				t.Fatalf("DowngradeServer(%s) error = %v", date, err)
+			}
+			want, err := DowngradeServer(server, CurrentSchema)
+			if err != nil {
+				t.Fatalf("DowngradeServer(%s) error = %v", CurrentSchema, err)
+			}
+
+			UpgradeServer(doc)
+			if !reflect.DeepEqual(doc, want) {
+				t.Errorf("%s round trip = %v, want %v", date, doc, want)
+			}
+		})
+	}
+}
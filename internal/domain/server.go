@@ -15,6 +15,12 @@ type ServerJSON struct {
 	Remotes     []Remote    `json:"remotes,omitempty" yaml:"remotes,omitempty"`
 }
 
+// ServerVersion is a historical server definition recovered from git
+type ServerVersion struct {
+	Info   VersionInfo
+	Server ServerJSON
+}
+
 // Repository contains source repository information
 type Repository struct {
 	URL    string `json:"url" yaml:"url" validate:"required,url"`
@@ -84,11 +90,59 @@ type KeyValueInput struct {
 type ServerMeta struct {
 	PublisherProvided map[string]interface{} `json:"io.modelcontextprotocol.registry/publisher-provided,omitempty" yaml:"io.modelcontextprotocol.registry/publisher-provided,omitempty"`
 	Official          *OfficialMeta          `json:"io.modelcontextprotocol.registry/official,omitempty" yaml:"io.modelcontextprotocol.registry/official,omitempty"`
+	Validation        *ValidationMeta        `json:"validation,omitempty" yaml:"-"`
+	Labels            map[string]string      `json:"labels,omitempty" yaml:"-"`
 }
 
-// OfficialMeta contains official registry metadata
+// ValidationMeta flags a server served despite failing validation
+type ValidationMeta struct {
+	Valid  bool              `json:"valid"`
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// OfficialMeta contains official registry metadata. PublishedAt is when the
+// server first appeared and UpdatedAt when its definition last changed,
+// according to git history where available.
 type OfficialMeta struct {
-	Status      string    `json:"status" yaml:"status"`
-	PublishedAt time.Time `json:"publishedAt" yaml:"publishedAt"`
-	IsLatest    bool      `json:"isLatest" yaml:"isLatest"`
+	Status        string     `json:"status" yaml:"status"`
+	StatusMessage string     `json:"statusMessage,omitempty" yaml:"statusMessage,omitempty"`
+	ReplacedBy    string     `json:"replacedBy,omitempty" yaml:"replacedBy,omitempty"`
+	DeprecatedAt  *time.Time `json:"deprecatedAt,omitempty" yaml:"deprecatedAt,omitempty"`
+	SunsetAt      *time.Time `json:"sunsetAt,omitempty" yaml:"sunsetAt,omitempty"`
+	PublishedAt   time.Time  `json:"publishedAt" yaml:"publishedAt"`
+	UpdatedAt     time.Time  `json:"updatedAt,omitzero" yaml:"updatedAt,omitempty"`
+	Commit        string     `json:"commit,omitempty" yaml:"commit,omitempty"`
+	IsLatest      bool       `json:"isLatest" yaml:"isLatest"`
+}
+
+// SetStatus records a server's lifecycle status; nil means active
+func (m *OfficialMeta) SetStatus(status *ServerStatus) {
+	if status == nil {
+		m.Status = StatusActive
+		return
+	}
+	m.Status = status.State
+	m.StatusMessage = status.Message
+	m.ReplacedBy = status.Replacement
+	m.DeprecatedAt = status.Since
+	m.SunsetAt = status.Sunset
+}
+
+// Lifecycle states of a server
+const (
+	StatusActive     = "active"
+	StatusDeprecated = "deprecated"
+	StatusDeleted    = "deleted"
+)
+
+// ServerStatus is the lifecycle block of a server definition or index
+// entry. Deprecated servers are still listed; deleted servers are
+// tombstones that stay resolvable by name but are hidden from listings.
+// Since defaults to when the block was committed.
+type ServerStatus struct {
+	State       string     `json:"state" yaml:"state"`
+	Message     string     `json:"message,omitempty" yaml:"message,omitempty"`
+	Replacement string     `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+	Since       *time.Time `json:"since,omitempty" yaml:"since,omitempty"`
+	Sunset      *time.Time `json:"sunset,omitempty" yaml:"sunset,omitempty"`
 }
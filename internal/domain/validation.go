@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"errors"
+	"reflect"
 	"regexp"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -16,6 +19,18 @@ var SemVerRegex = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(
 func NewValidator() *validator.Validate {
 	v := validator.New()
 
+	// Report field paths using JSON names, e.g. packages[0].transport.type
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+
 	// Register custom server name validation
 	_ = v.RegisterValidation("server_name", func(fl validator.FieldLevel) bool {
 		return ServerNameRegex.MatchString(fl.Field().String())
@@ -29,8 +44,65 @@ func NewValidator() *validator.Validate {
 	return v
 }
 
+var defaultValidator = NewValidator()
+
 // ValidateServer validates a ServerJSON struct
 func ValidateServer(server *ServerJSON) error {
-	v := NewValidator()
-	return v.Struct(server)
+	return defaultValidator.Struct(server)
+}
+
+// ValidationIssue is a single rule a server definition failed. Advisory
+// issues come from a schema that is not the upstream document and do not
+// make the definition invalid.
+type ValidationIssue struct {
+	Field    string `json:"field"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Advisory bool   `json:"advisory,omitempty"`
+}
+
+// ValidationIssues validates a server and returns one issue per failed
+// rule, or nil if the server is valid
+func ValidationIssues(server *ServerJSON) []ValidationIssue {
+	err := ValidateServer(server)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []ValidationIssue{{Message: err.Error()}}
+	}
+
+	issues := make([]ValidationIssue, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		// Namespace is prefixed with the root struct name
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+		issues = append(issues, ValidationIssue{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Message: issueMessage(field, fe),
+		})
+	}
+	return issues
+}
+
+func issueMessage(field string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "min", "max":
+		return field + " must satisfy " + fe.Tag() + "=" + fe.Param()
+	case "oneof":
+		return field + " must be one of: " + fe.Param()
+	case "server_name":
+		return field + " must be in reverse-DNS form, e.g. io.github.user/server"
+	case "semver":
+		return field + " must be a semantic version"
+	default:
+		if fe.Param() != "" {
+			return field + " failed " + fe.Tag() + "=" + fe.Param()
+		}
+		return field + " failed " + fe.Tag()
+	}
 }
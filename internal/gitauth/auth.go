@@ -0,0 +1,111 @@
+package gitauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+
+	"github.com/mcpregistry/server/internal/github"
+)
+
+// Provider supplies credentials for git fetch and clone operations
+type Provider interface {
+	// AuthMethod returns the credentials to use for the next remote
+	// operation. A nil method means the remote is accessed anonymously.
+	AuthMethod(ctx context.Context) (transport.AuthMethod, error)
+}
+
+// GitHubApp authenticates with short-lived GitHub App installation tokens
+type GitHubApp struct {
+	app *github.AppAuth
+}
+
+// NewGitHubApp wraps a GitHub App authenticator
+func NewGitHubApp(app *github.AppAuth) *GitHubApp {
+	return &GitHubApp{app: app}
+}
+
+// AuthMethod returns basic auth with a fresh installation token
+func (p *GitHubApp) AuthMethod(ctx context.Context) (transport.AuthMethod, error) {
+	token, err := p.app.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.BasicAuth{
+		Username: "x-access-token",
+		Password: token,
+	}, nil
+}
+
+// Token authenticates over HTTPS with a static personal access token
+type Token struct {
+	auth *http.BasicAuth
+}
+
+// NewToken creates a static token provider. Most forges ignore the
+// username when a token is used; it defaults to "x-access-token".
+func NewToken(username, token string) (*Token, error) {
+	if token == "" {
+		return nil, errors.New("token is required")
+	}
+	if username == "" {
+		username = "x-access-token"
+	}
+
+	return &Token{
+		auth: &http.BasicAuth{
+			Username: username,
+			Password: token,
+		},
+	}, nil
+}
+
+// AuthMethod returns the static token credentials
+func (p *Token) AuthMethod(_ context.Context) (transport.AuthMethod, error) {
+	return p.auth, nil
+}
+
+// SSHKey authenticates over SSH with a deploy key. Host keys are always
+// checked against known_hosts.
+type SSHKey struct {
+	auth *ssh.PublicKeys
+}
+
+// NewSSHKey loads a PEM-encoded private key and known_hosts files.
+// With no knownHosts files, SSH_KNOWN_HOSTS or the default locations are used.
+func NewSSHKey(user string, privateKey []byte, passphrase string, knownHosts ...string) (*SSHKey, error) {
+	if user == "" {
+		user = "git"
+	}
+
+	keys, err := ssh.NewPublicKeys(user, privateKey, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH private key: %w", err)
+	}
+
+	db, err := ssh.NewKnownHostsDb(knownHosts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts: %w", err)
+	}
+	keys.HostKeyCallback = db.HostKeyCallback()
+
+	return &SSHKey{auth: keys}, nil
+}
+
+// AuthMethod returns the deploy key credentials
+func (p *SSHKey) AuthMethod(_ context.Context) (transport.AuthMethod, error) {
+	return p.auth, nil
+}
+
+// Anonymous accesses public repositories without credentials
+type Anonymous struct{}
+
+// AuthMethod returns no credentials
+func (Anonymous) AuthMethod(_ context.Context) (transport.AuthMethod, error) {
+	return nil, nil
+}
@@ -0,0 +1,152 @@
+package gitauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestToken(t *testing.T) {
+	tests := []struct {
+		name         string
+		username     string
+		token        string
+		wantUsername string
+		wantErr      bool
+	}{
+		{name: "default username", token: "secret", wantUsername: "x-access-token"},
+		{name: "explicit username", username: "deploy", token: "secret", wantUsername: "deploy"},
+		{name: "missing token", username: "deploy", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewToken(tt.username, tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewToken succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewToken: %v", err)
+			}
+
+			method, err := p.AuthMethod(context.Background())
+			if err != nil {
+				t.Fatalf("AuthMethod: %v", err)
+			}
+			basic, ok := method.(*http.BasicAuth)
+			if !ok {
+				t.Fatalf("AuthMethod = %T, want *http.BasicAuth", method)
+			}
+			if basic.Username != tt.wantUsername || basic.Password != tt.token {
+				t.Errorf("credentials = %s:%s, want %s:%s", basic.Username, basic.Password, tt.wantUsername, tt.token)
+			}
+		})
+	}
+}
+
+// sshKey returns a PEM-encoded private key and its public key
+func sshKey(t *testing.T, passphrase string) ([]byte, ssh.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(priv, "")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(block), sshPub
+}
+
+func TestSSHKey(t *testing.T) {
+	key, _ := sshKey(t, "")
+	encrypted, _ := sshKey(t, "hunter2")
+	_, hostKey := sshKey(t, "")
+	_, otherHostKey := sshKey(t, "")
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{"git.example.com"}, hostKey)
+	if err := os.WriteFile(knownHosts, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		user       string
+		key        []byte
+		passphrase string
+		knownHosts string
+		wantUser   string
+		wantErr    bool
+	}{
+		{name: "default user", key: key, knownHosts: knownHosts, wantUser: "git"},
+		{name: "explicit user", user: "deploy", key: key, knownHosts: knownHosts, wantUser: "deploy"},
+		{name: "encrypted key", key: encrypted, passphrase: "hunter2", knownHosts: knownHosts, wantUser: "git"},
+		{name: "wrong passphrase", key: encrypted, passphrase: "wrong", knownHosts: knownHosts, wantErr: true},
+		{name: "malformed key", key: []byte("not a key"), knownHosts: knownHosts, wantErr: true},
+		{name: "missing known_hosts", key: key, knownHosts: filepath.Join(t.TempDir(), "missing"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewSSHKey(tt.user, tt.key, tt.passphrase, tt.knownHosts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewSSHKey succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSSHKey: %v", err)
+			}
+
+			method, err := p.AuthMethod(context.Background())
+			if err != nil {
+				t.Fatalf("AuthMethod: %v", err)
+			}
+			keys, ok := method.(*gitssh.PublicKeys)
+			if !ok {
+				t.Fatalf("AuthMethod = %T, want *ssh.PublicKeys", method)
+			}
+			if keys.User != tt.wantUser {
+				t.Errorf("user = %q, want %q", keys.User, tt.wantUser)
+			}
+
+			// Host keys are checked against known_hosts
+			addr := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 22}
+			if err := keys.HostKeyCallback("git.example.com:22", addr, hostKey); err != nil {
+				t.Errorf("known host key rejected: %v", err)
+			}
+			if err := keys.HostKeyCallback("git.example.com:22", addr, otherHostKey); err == nil {
+				t.Error("unknown host key accepted")
+			}
+		})
+	}
+}
+
+func TestAnonymous(t *testing.T) {
+	method, err := Anonymous{}.AuthMethod(context.Background())
+	if err != nil || method != nil {
+		t.Errorf("AuthMethod = %v, %v; want no credentials", method, err)
+	}
+}
@@ -0,0 +1,264 @@
+package gitstore
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// fileChanges is the change history of every file as of one revision
+type fileChanges struct {
+	revision string
+	files    map[string]source.FileChange
+}
+
+// FileChanges returns when each file was created and last modified along
+// the first-parent history of revision, so a change merged from a branch
+// dates from the merge. The result of the previous call is reused when it
+// is an ancestor of revision, so each sync only walks the new commits.
+func (s *Store) FileChanges(revision string) (map[string]source.FileChange, error) {
+	s.changesMu.Lock()
+	defer s.changesMu.Unlock()
+
+	if s.changes != nil && s.changes.revision == revision {
+		return s.changes.files, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.repo == nil {
+		return nil, errors.New("repository not initialized")
+	}
+
+	var known string
+	if s.changes != nil {
+		known = s.changes.revision
+	}
+
+	fresh := make(map[string]source.FileChange)
+	reachedKnown := false
+	hash := plumbing.NewHash(revision)
+	for !hash.IsZero() {
+		if hash.String() == known {
+			reachedKnown = true
+			break
+		}
+
+		commit, err := s.repo.CommitObject(hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit %s: %w", hash, err)
+		}
+		parent, err := firstParent(commit)
+		if err != nil {
+			return nil, err
+		}
+		if err := recordChanges(fresh, commit, parent); err != nil {
+			return nil, err
+		}
+
+		hash = plumbing.ZeroHash
+		if parent != nil {
+			hash = parent.Hash
+		}
+	}
+
+	files := fresh
+	if reachedKnown {
+		files = make(map[string]source.FileChange, len(s.changes.files)+len(fresh))
+		for p, fc := range s.changes.files {
+			files[p] = fc
+		}
+		for p, fc := range fresh {
+			if old, ok := files[p]; ok {
+				fc.Created = old.Created
+			}
+			files[p] = fc
+		}
+	}
+
+	s.changes = &fileChanges{revision: revision, files: files}
+	return files, nil
+}
+
+// ChangedPaths returns the files that differ between the trees of two
+// commits. Renames are reported as both paths.
+func (s *Store) ChangedPaths(from, to string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.repo == nil {
+		return nil, errors.New("repository not initialized")
+	}
+
+	fromTree, err := s.commitTree(from)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := s.commitTree(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s..%s: %w", from, to, err)
+	}
+
+	seen := make(map[string]bool, len(changes))
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		for _, path := range []string{change.From.Name, change.To.Name} {
+			if path != "" && !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// ResolveCommit expands a full or abbreviated commit hash, which must be
+// revision itself or one of its ancestors
+func (s *Store) ResolveCommit(revision, ref string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.repo == nil {
+		return "", errors.New("repository not initialized")
+	}
+	if !isHex(ref) || len(ref) < 4 || len(ref) > 40 {
+		return "", fmt.Errorf("%w: %q is not a commit hash", source.ErrUnknownCommit, ref)
+	}
+
+	hash, err := s.repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", source.ErrUnknownCommit, ref)
+	}
+	commit, err := s.repo.CommitObject(*hash)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", source.ErrUnknownCommit, ref)
+	}
+	head, err := s.repo.CommitObject(plumbing.NewHash(revision))
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit %s: %w", revision, err)
+	}
+
+	if commit.Hash != head.Hash {
+		ancestor, err := commit.IsAncestor(head)
+		if err != nil {
+			return "", fmt.Errorf("failed to walk history of %s: %w", revision, err)
+		}
+		if !ancestor {
+			return "", fmt.Errorf("%w: %s is not in the history of %s", source.ErrUnknownCommit, ref, revision)
+		}
+	}
+	return commit.Hash.String(), nil
+}
+
+// CommitAt returns the newest first-parent ancestor of revision committed
+// at or before t, or "" if the history starts later
+func (s *Store) CommitAt(revision string, t time.Time) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.repo == nil {
+		return "", errors.New("repository not initialized")
+	}
+
+	commit, err := s.repo.CommitObject(plumbing.NewHash(revision))
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit %s: %w", revision, err)
+	}
+	for commit != nil {
+		if !commit.Committer.When.After(t) {
+			return commit.Hash.String(), nil
+		}
+		if commit, err = firstParent(commit); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// commitTree returns the tree of a commit. Callers must hold s.mu.
+func (s *Store) commitTree(revision string) (*object.Tree, error) {
+	commit, err := s.repo.CommitObject(plumbing.NewHash(revision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", revision, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree for %s: %w", revision, err)
+	}
+	return tree, nil
+}
+
+// firstParent returns a commit's first parent, or nil for a root commit
+func firstParent(c *object.Commit) (*object.Commit, error) {
+	if c.NumParents() == 0 {
+		return nil, nil
+	}
+	parent, err := c.Parent(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent of %s: %w", c.Hash, err)
+	}
+	return parent, nil
+}
+
+// recordChanges notes the files a commit added or modified relative to its
+// parent. Commits are visited newest first, so the first visit of a path
+// sets its last change and every visit moves its creation further back.
+func recordChanges(files map[string]source.FileChange, c, parent *object.Commit) error {
+	tree, err := c.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to get tree for %s: %w", c.Hash, err)
+	}
+
+	var parentTree *object.Tree
+	if parent != nil {
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return fmt.Errorf("failed to get tree for %s: %w", parent.Hash, err)
+		}
+	}
+
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return fmt.Errorf("failed to diff %s: %w", c.Hash, err)
+	}
+
+	when := c.Committer.When
+	for _, change := range changes {
+		path := change.To.Name
+		if path == "" {
+			continue // deleted
+		}
+
+		fc, seen := files[path]
+		if !seen {
+			fc.Updated = when
+			fc.Commit = c.Hash.String()
+		}
+		fc.Created = when
+		files[path] = fc
+	}
+
+	return nil
+}
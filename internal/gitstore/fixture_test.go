@@ -0,0 +1,140 @@
+package gitstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// upstream is a repository on disk that stores clone, committing one hour
+// apart from a fixed start
+type upstream struct {
+	t        *testing.T
+	dir      string
+	repo     *git.Repository
+	worktree *git.Worktree
+	when     time.Time
+	signer   git.Signer // signs commits when set
+}
+
+func newUpstream(t *testing.T) *upstream {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	if err != nil {
+		t.Fatalf("init upstream: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("upstream worktree: %v", err)
+	}
+	return &upstream{
+		t:        t,
+		dir:      dir,
+		repo:     repo,
+		worktree: worktree,
+		when:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (u *upstream) signature() *object.Signature {
+	return &object.Signature{Name: "Upstream", Email: "upstream@example.com", When: u.when}
+}
+
+// commit writes files and commits them, returning the commit hash
+func (u *upstream) commit(files map[string]string) plumbing.Hash {
+	u.t.Helper()
+	for name, content := range files {
+		path := filepath.Join(u.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			u.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			u.t.Fatal(err)
+		}
+		if _, err := u.worktree.Add(name); err != nil {
+			u.t.Fatalf("add %s: %v", name, err)
+		}
+	}
+	hash, err := u.worktree.Commit(fmt.Sprintf("commit at %s", u.when.Format(time.RFC3339)), &git.CommitOptions{
+		Author:    u.signature(),
+		Committer: u.signature(),
+		Signer:    u.signer,
+	})
+	if err != nil {
+		u.t.Fatalf("commit: %v", err)
+	}
+	u.when = u.when.Add(time.Hour)
+	return hash
+}
+
+// tag points a lightweight tag at a commit, moving it if it exists
+func (u *upstream) tag(name string, hash plumbing.Hash) {
+	u.t.Helper()
+	ref := plumbing.NewHashReference(plumbing.NewTagReferenceName(name), hash)
+	if err := u.repo.Storer.SetReference(ref); err != nil {
+		u.t.Fatalf("tag %s: %v", name, err)
+	}
+}
+
+// annotatedTag creates an annotated tag dated now on the upstream clock
+func (u *upstream) annotatedTag(name string, hash plumbing.Hash) {
+	u.t.Helper()
+	_, err := u.repo.CreateTag(name, hash, &git.CreateTagOptions{
+		Tagger:  u.signature(),
+		Message: name,
+	})
+	if err != nil {
+		u.t.Fatalf("tag %s: %v", name, err)
+	}
+	u.when = u.when.Add(time.Hour)
+}
+
+// disconnect makes the upstream unreachable until the test restores it
+func (u *upstream) disconnect() (restore func()) {
+	u.t.Helper()
+	gone := u.dir + ".gone"
+	if err := os.Rename(u.dir, gone); err != nil {
+		u.t.Fatal(err)
+	}
+	return func() {
+		if err := os.Rename(gone, u.dir); err != nil {
+			u.t.Fatal(err)
+		}
+	}
+}
+
+// clone creates a store tracking the upstream and clones it
+func (u *upstream) clone(cfg Config) (*Store, error) {
+	u.t.Helper()
+	cfg.RepoURL = u.dir
+	if cfg.Logger == nil {
+		cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	s, err := New(cfg)
+	if err != nil {
+		u.t.Fatalf("New: %v", err)
+	}
+	return s, s.Clone(context.Background())
+}
+
+// mustClone is clone for stores that must come up
+func (u *upstream) mustClone(cfg Config) *Store {
+	u.t.Helper()
+	s, err := u.clone(cfg)
+	if err != nil {
+		u.t.Fatalf("Clone: %v", err)
+	}
+	return s
+}
@@ -5,38 +5,58 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
 
-	"github.com/mcpregistry/server/internal/github"
+	"github.com/mcpregistry/server/internal/gitauth"
+	"github.com/mcpregistry/server/internal/source"
 )
 
 // Store provides disk-based git repository access
 type Store struct {
-	config       Config
-	repo         *git.Repository
-	worktree     *git.Worktree
+	config        Config
+	repo          *git.Repository
+	worktree      *git.Worktree // nil in memory mode
+	tree          *object.Tree  // tree of the commit being served
 	currentCommit string
-	mu           sync.RWMutex
-	logger       *slog.Logger
+	currentTag    taggedCommit // tag being served in tag mode
+	staleReason   string       // why the served commit may be behind, "" if current
+	verification  *source.Verification
+	mu            sync.RWMutex
+	logger        *slog.Logger
+
+	changes   *fileChanges // last FileChanges result
+	changesMu sync.Mutex
 }
 
-// Config holds git store configuration
+// Config holds git store configuration.
+// By default the store tracks Branch. Setting TagPattern tracks the newest
+// tag matching the glob instead, and setting Commit pins a single commit.
+// InMemory keeps the whole repository in memory and serves reads from git
+// tree objects with no worktree, so no writable LocalPath is needed.
 type Config struct {
-	RepoURL   string
-	Branch    string
-	LocalPath string
-	Auth      *github.AppAuth
-	Logger    *slog.Logger
+	RepoURL    string
+	Branch     string
+	TagPattern string
+	Commit     string
+	LocalPath  string
+	InMemory   bool
+	Auth       gitauth.Provider // nil for anonymous access
+	Verifier   Verifier         // nil disables signature verification
+	Logger     *slog.Logger
 }
 
 // New creates a new git store instance
@@ -44,15 +64,26 @@ func New(cfg Config) (*Store, error) {
 	if cfg.RepoURL == "" {
 		return nil, errors.New("repo URL is required")
 	}
-	if cfg.LocalPath == "" {
+	if cfg.LocalPath == "" && !cfg.InMemory {
 		return nil, errors.New("local path is required")
 	}
 	if cfg.Branch == "" {
 		cfg.Branch = "main"
 	}
+	if cfg.TagPattern != "" && cfg.Commit != "" {
+		return nil, errors.New("tag pattern and pinned commit are mutually exclusive")
+	}
+	if cfg.TagPattern != "" {
+		if _, err := path.Match(cfg.TagPattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid tag pattern: %w", err)
+		}
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = slog.Default()
 	}
+	if cfg.Auth == nil {
+		cfg.Auth = gitauth.Anonymous{}
+	}
 
 	return &Store{
 		config: cfg,
@@ -60,19 +91,159 @@ func New(cfg Config) (*Store, error) {
 	}, nil
 }
 
-// Clone performs initial repository clone with context timeout
+// Clone prepares the repository for serving. An existing clone at LocalPath
+// is reused and fetched; if that update fails the on-disk copy of the
+// tracked target is served in a stale state. Tag and commit modes resolve
+// their target in the local clone, so a failed fetch never serves a HEAD
+// that is not the pinned commit or a matching tag. A fresh clone is made
+// only when there is no usable local repository. In memory mode every
+// start is a fresh clone.
 func (s *Store) Clone(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Ensure parent directory exists
-	if err := os.MkdirAll(filepath.Dir(s.config.LocalPath), 0755); err != nil {
-		return fmt.Errorf("failed to create parent directory: %w", err)
+	if s.config.InMemory {
+		return s.cloneFresh(ctx)
+	}
+
+	repo, err := s.openExisting()
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return s.cloneFresh(ctx)
+	}
+	if err != nil {
+		s.logger.Warn("existing clone not usable, re-cloning",
+			"path", s.config.LocalPath,
+			"reason", err,
+		)
+		return s.cloneFresh(ctx)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	s.repo = repo
+	s.worktree = worktree
+
+	if err := s.serveLocal(); err != nil {
+		// Nothing on disk may be served; the target must come from the remote
+		s.logger.Warn("existing clone has no servable commit",
+			"mode", s.mode(),
+			"ref", s.ref(),
+			"reason", err,
+		)
+	} else {
+		s.logger.Info("reusing existing clone",
+			"path", s.config.LocalPath,
+			"commit", s.currentCommit,
+			"tag", s.currentTag.name,
+		)
+	}
+
+	if _, err := s.pull(ctx); err != nil {
+		if s.currentCommit == "" {
+			return fmt.Errorf("existing clone has no servable %s %s and update failed: %w", s.mode(), s.ref(), err)
+		}
+		s.logger.Warn(staleMessages[s.staleReason],
+			"reason", s.staleReason,
+			"commit", s.currentCommit,
+			"error", err,
+		)
+		return nil
+	}
+
+	s.logger.Info("existing clone updated", "commit", s.currentCommit)
+	return nil
+}
+
+// serveLocal serves what an existing clone already holds: the checked out
+// branch in branch mode, and the target resolved from local refs in tag and
+// commit modes
+func (s *Store) serveLocal() error {
+	var target taggedCommit
+	if s.mode() == modeBranch {
+		head, err := s.repo.Head()
+		if err != nil {
+			return fmt.Errorf("failed to get current commit: %w", err)
+		}
+		target.commit = head.Hash()
+	} else {
+		var err error
+		if target, err = s.resolveTarget(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.verifyCommit(target.commit); err != nil {
+		return err
+	}
+	load := s.checkout
+	if s.mode() == modeBranch {
+		// The worktree is already at HEAD
+		load = s.loadTree
+	}
+	if err := load(target.commit); err != nil {
+		return err
+	}
+	s.currentCommit = target.commit.String()
+	s.currentTag = target
+	return nil
+}
+
+// openExisting opens the repository at LocalPath and checks that it is a
+// complete clone of the configured remote and branch
+func (s *Store) openExisting() (*git.Repository, error) {
+	repo, err := git.PlainOpen(s.config.LocalPath)
+	if err != nil {
+		return nil, err
 	}
 
-	// Remove existing directory if present (clean clone)
-	if err := os.RemoveAll(s.config.LocalPath); err != nil {
-		return fmt.Errorf("failed to clean existing directory: %w", err)
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return nil, err
+	}
+	if urls := remote.Config().URLs; len(urls) == 0 || urls[0] != s.config.RepoURL {
+		return nil, fmt.Errorf("origin points at %v, want %s", urls, s.config.RepoURL)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("unreadable HEAD: %w", err)
+	}
+	if s.mode() == modeBranch {
+		if want := plumbing.NewBranchReferenceName(s.config.Branch); head.Name() != want {
+			return nil, fmt.Errorf("checked out %s, want %s", head.Name(), want)
+		}
+	}
+	if _, err := repo.CommitObject(head.Hash()); err != nil {
+		return nil, fmt.Errorf("HEAD commit unreadable: %w", err)
+	}
+
+	// Clones made before version history was served were shallow
+	shallow, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil, err
+	}
+	if len(shallow) > 0 {
+		return nil, errors.New("shallow clone")
+	}
+
+	return repo, nil
+}
+
+// cloneFresh removes anything at LocalPath and clones from scratch
+func (s *Store) cloneFresh(ctx context.Context) error {
+	if !s.config.InMemory {
+		// Ensure parent directory exists
+		if err := os.MkdirAll(filepath.Dir(s.config.LocalPath), 0755); err != nil {
+			return fmt.Errorf("failed to create parent directory: %w", err)
+		}
+
+		// Remove existing directory if present (clean clone)
+		if err := os.RemoveAll(s.config.LocalPath); err != nil {
+			return fmt.Errorf("failed to clean existing directory: %w", err)
+		}
 	}
 
 	auth, err := s.getAuth(ctx)
@@ -82,42 +253,71 @@ func (s *Store) Clone(ctx context.Context) error {
 
 	s.logger.Info("cloning repository",
 		"url", s.config.RepoURL,
-		"branch", s.config.Branch,
+		"mode", s.mode(),
+		"ref", s.ref(),
 		"path", s.config.LocalPath,
+		"in_memory", s.config.InMemory,
 	)
 
 	cloneOpts := &git.CloneOptions{
-		URL:           s.config.RepoURL,
-		Auth:          auth,
-		Depth:         1, // Shallow clone for efficiency
+		URL:  s.config.RepoURL,
+		Auth: auth,
+		// Full history is required to serve per-server version history
 		SingleBranch:  true,
 		ReferenceName: plumbing.NewBranchReferenceName(s.config.Branch),
 		Progress:      nil,
 	}
-
-	repo, err := git.PlainCloneContext(ctx, s.config.LocalPath, false, cloneOpts)
-	if err != nil {
-		return fmt.Errorf("clone failed: %w", err)
+	if s.mode() != modeBranch {
+		// Tags and pinned commits may live on any branch
+		cloneOpts.SingleBranch = false
+		cloneOpts.ReferenceName = ""
+		cloneOpts.NoCheckout = true
+		cloneOpts.Tags = git.AllTags
 	}
 
-	worktree, err := repo.Worktree()
-	if err != nil {
-		return fmt.Errorf("failed to get worktree: %w", err)
+	var repo *git.Repository
+	var worktree *git.Worktree
+	if s.config.InMemory {
+		// No worktree: reads are served from tree objects
+		cloneOpts.NoCheckout = true
+		repo, err = git.CloneContext(ctx, memory.NewStorage(), nil, cloneOpts)
+		if err != nil {
+			return fmt.Errorf("clone failed: %w", err)
+		}
+	} else {
+		repo, err = git.PlainCloneContext(ctx, s.config.LocalPath, false, cloneOpts)
+		if err != nil {
+			return fmt.Errorf("clone failed: %w", err)
+		}
+		worktree, err = repo.Worktree()
+		if err != nil {
+			return fmt.Errorf("failed to get worktree: %w", err)
+		}
 	}
 
 	s.repo = repo
 	s.worktree = worktree
+	s.staleReason = ""
 
-	// Get current commit
-	if err := s.updateCurrentCommit(); err != nil {
-		return fmt.Errorf("failed to get current commit: %w", err)
+	target, err := s.resolveTarget()
+	if err != nil {
+		return err
 	}
+	if err := s.verifyCommit(target.commit); err != nil {
+		return err
+	}
+	if err := s.checkout(target.commit); err != nil {
+		return err
+	}
+	s.currentCommit = target.commit.String()
+	s.currentTag = target
 
-	s.logger.Info("clone completed", "commit", s.currentCommit)
+	s.logger.Info("clone completed", "commit", s.currentCommit, "tag", s.currentTag.name)
 	return nil
 }
 
-// Pull fetches and merges changes from remote
+// Pull fetches from the remote and checks out the tracked branch, newest
+// matching tag or pinned commit
 func (s *Store) Pull(ctx context.Context) (bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -126,39 +326,254 @@ func (s *Store) Pull(ctx context.Context) (bool, error) {
 		return false, errors.New("repository not initialized")
 	}
 
+	return s.pull(ctx)
+}
+
+// Reasons the served commit may be behind upstream
+const (
+	staleAuthFailed     = "auth_failed"
+	staleFetchFailed    = "fetch_failed"
+	staleUnresolved     = "target_unresolved"
+	staleUnverified     = "verification_failed"
+	staleCheckoutFailed = "checkout_failed"
+)
+
+var staleMessages = map[string]string{
+	staleAuthFailed:     "git auth failed, serving stale on-disk commit",
+	staleFetchFailed:    "fetch failed, serving stale on-disk commit",
+	staleUnresolved:     "tracked target not found after fetch, serving stale on-disk commit",
+	staleUnverified:     "fetched commit failed verification, serving stale on-disk commit",
+	staleCheckoutFailed: "checkout of fetched commit failed, serving stale on-disk commit",
+}
+
+// pull does the work of Pull; callers must hold the write lock. Every
+// failure records its own stale reason, since the commit served before the
+// call keeps being served.
+func (s *Store) pull(ctx context.Context) (bool, error) {
 	oldCommit := s.currentCommit
 
 	auth, err := s.getAuth(ctx)
 	if err != nil {
+		s.staleReason = staleAuthFailed
 		return false, fmt.Errorf("failed to get auth: %w", err)
 	}
 
-	err = s.worktree.PullContext(ctx, &git.PullOptions{
+	fetchOpts := &git.FetchOptions{
 		RemoteName: "origin",
 		Auth:       auth,
 		Force:      true,
-	})
+	}
+	if s.mode() != modeBranch {
+		fetchOpts.Tags = git.AllTags
+	}
 
-	if errors.Is(err, git.NoErrAlreadyUpToDate) {
-		return false, nil
+	err = s.repo.FetchContext(ctx, fetchOpts)
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		s.staleReason = staleFetchFailed
+		return false, fmt.Errorf("fetch failed: %w", err)
 	}
+
+	target, err := s.resolveTarget()
 	if err != nil {
-		return false, fmt.Errorf("pull failed: %w", err)
+		s.staleReason = staleUnresolved
+		return false, err
 	}
 
-	if err := s.updateCurrentCommit(); err != nil {
-		return false, fmt.Errorf("failed to update commit: %w", err)
+	if target.commit.String() == oldCommit {
+		s.currentTag = target
+		s.staleReason = ""
+		return false, nil
 	}
 
-	changed := oldCommit != s.currentCommit
-	if changed {
-		s.logger.Info("repository updated",
-			"old_commit", oldCommit,
-			"new_commit", s.currentCommit,
+	// The tag being served may have moved or been deleted since; it is
+	// compared as it was when it was served
+	if s.mode() == modeTag && oldCommit != "" && !newerTag(target, s.currentTag) {
+		s.logger.Warn("ignoring matching tag older than the current one",
+			"tag", target.name,
+			"current_tag", s.currentTag.name,
 		)
+		s.staleReason = ""
+		return false, nil
+	}
+
+	if err := s.verifyCommit(target.commit); err != nil {
+		s.staleReason = staleUnverified
+		return false, err
+	}
+
+	if err := s.checkout(target.commit); err != nil {
+		s.staleReason = staleCheckoutFailed
+		return false, err
+	}
+
+	s.currentCommit = target.commit.String()
+	s.currentTag = target
+	s.staleReason = ""
+
+	s.logger.Info("repository updated",
+		"old_commit", oldCommit,
+		"new_commit", s.currentCommit,
+		"tag", s.currentTag.name,
+	)
+
+	return true, nil
+}
+
+// Tracking modes
+const (
+	modeBranch = "branch"
+	modeTag    = "tag"
+	modeCommit = "commit"
+)
+
+func (s *Store) mode() string {
+	switch {
+	case s.config.Commit != "":
+		return modeCommit
+	case s.config.TagPattern != "":
+		return modeTag
+	default:
+		return modeBranch
+	}
+}
+
+// ref returns the branch, tag pattern or commit being tracked
+func (s *Store) ref() string {
+	switch s.mode() {
+	case modeCommit:
+		return s.config.Commit
+	case modeTag:
+		return s.config.TagPattern
+	default:
+		return s.config.Branch
+	}
+}
+
+// resolveTarget returns the commit that should be served given the fetched
+// refs, with the tag that selected it in tag mode
+func (s *Store) resolveTarget() (taggedCommit, error) {
+	switch s.mode() {
+	case modeCommit:
+		hash := plumbing.NewHash(s.config.Commit)
+		if _, err := s.repo.CommitObject(hash); err != nil {
+			return taggedCommit{}, fmt.Errorf("pinned commit %s not found: %w", s.config.Commit, err)
+		}
+		return taggedCommit{commit: hash}, nil
+
+	case modeTag:
+		tags, err := s.matchingTags()
+		if err != nil {
+			return taggedCommit{}, err
+		}
+		if len(tags) == 0 {
+			return taggedCommit{}, fmt.Errorf("no tags match %q", s.config.TagPattern)
+		}
+		return tags[0], nil
+
+	default:
+		ref, err := s.repo.Reference(plumbing.NewRemoteReferenceName("origin", s.config.Branch), true)
+		if err != nil {
+			return taggedCommit{}, fmt.Errorf("failed to resolve origin/%s: %w", s.config.Branch, err)
+		}
+		return taggedCommit{commit: ref.Hash()}, nil
+	}
+}
+
+// taggedCommit is a commit to serve and, in tag mode, the tag naming it
+type taggedCommit struct {
+	name   string
+	commit plumbing.Hash
+	when   time.Time // tagger time of annotated tags, else commit time
+}
+
+// matchingTags returns tags matching the pattern, newest first as ordered
+// by newerTag
+func (s *Store) matchingTags() ([]taggedCommit, error) {
+	iter, err := s.repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer iter.Close()
+
+	var tags []taggedCommit
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if ok, _ := path.Match(s.config.TagPattern, name); !ok {
+			return nil
+		}
+
+		commit, err := s.peelTag(ref.Hash())
+		if err != nil {
+			s.logger.Debug("skipping tag", "tag", name, "error", err)
+			return nil
+		}
+
+		when := commit.Committer.When
+		if tag, err := s.repo.TagObject(ref.Hash()); err == nil {
+			when = tag.Tagger.When
+		}
+		tags = append(tags, taggedCommit{
+			name:   name,
+			commit: commit.Hash,
+			when:   when,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return newerTag(tags[i], tags[j])
+	})
+
+	return tags, nil
+}
+
+// peelTag resolves a lightweight or annotated tag to its commit
+func (s *Store) peelTag(hash plumbing.Hash) (*object.Commit, error) {
+	if tag, err := s.repo.TagObject(hash); err == nil {
+		return tag.Commit()
 	}
+	return s.repo.CommitObject(hash)
+}
 
-	return changed, nil
+// checkout moves the worktree to a commit. Branch mode resets the branch;
+// tag and commit modes use a detached HEAD. In memory mode there is no
+// worktree and only the served tree is swapped.
+func (s *Store) checkout(hash plumbing.Hash) error {
+	var err error
+	switch {
+	case s.worktree == nil:
+	case s.mode() == modeBranch:
+		err = s.worktree.Reset(&git.ResetOptions{
+			Commit: hash,
+			Mode:   git.HardReset,
+		})
+	default:
+		err = s.worktree.Checkout(&git.CheckoutOptions{
+			Hash:  hash,
+			Force: true,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check out %s: %w", hash, err)
+	}
+	return s.loadTree(hash)
+}
+
+// loadTree sets the tree that reads are served from
+func (s *Store) loadTree(hash plumbing.Hash) error {
+	commit, err := s.repo.CommitObject(hash)
+	if err != nil {
+		return fmt.Errorf("failed to get commit %s: %w", hash, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to get tree for %s: %w", hash, err)
+	}
+	s.tree = tree
+	return nil
 }
 
 // PullWithRetry attempts to pull with exponential backoff
@@ -171,6 +586,10 @@ func (s *Store) PullWithRetry(ctx context.Context, maxRetries int) (bool, error)
 		if err == nil {
 			return changed, nil
 		}
+		if errors.Is(err, ErrUnverifiedCommit) {
+			// Retrying will not make an untrusted commit trusted
+			return false, err
+		}
 
 		lastErr = err
 		s.logger.Warn("pull attempt failed",
@@ -194,7 +613,8 @@ func (s *Store) PullWithRetry(ctx context.Context, maxRetries int) (bool, error)
 	return false, fmt.Errorf("pull failed after %d retries: %w", maxRetries, lastErr)
 }
 
-// ReadFile reads a file from the current HEAD
+// ReadFile reads a file from the served commit. Reads go through git
+// objects, so paths cannot escape the repository and symlinks are refused.
 func (s *Store) ReadFile(path string) ([]byte, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -203,8 +623,37 @@ func (s *Store) ReadFile(path string) ([]byte, error) {
 		return nil, errors.New("repository not initialized")
 	}
 
-	fullPath := filepath.Join(s.config.LocalPath, path)
-	return os.ReadFile(fullPath)
+	return readTreeFile(s.tree, path)
+}
+
+// ReadFileAt reads a file as of a specific commit
+func (s *Store) ReadFileAt(revision, path string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.repo == nil {
+		return nil, errors.New("repository not initialized")
+	}
+
+	tree, err := s.commitTree(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return readTreeFile(tree, path)
+}
+
+func readTreeFile(tree *object.Tree, path string) ([]byte, error) {
+	f, err := treeFile(tree, path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := f.Contents()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
 }
 
 // ListFiles returns all files in a directory
@@ -216,16 +665,23 @@ func (s *Store) ListFiles(dir string) ([]string, error) {
 		return nil, errors.New("repository not initialized")
 	}
 
-	fullPath := filepath.Join(s.config.LocalPath, dir)
-	entries, err := os.ReadDir(fullPath)
+	cleaned, err := source.CleanPath(dir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
+		return nil, err
+	}
+
+	tree := s.tree
+	if cleaned != "." {
+		tree, err = s.tree.Tree(cleaned)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory: %w", treeError(dir, err))
+		}
 	}
 
 	var files []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			files = append(files, entry.Name())
+	for _, entry := range tree.Entries {
+		if entry.Mode.IsRegular() {
+			files = append(files, entry.Name)
 		}
 	}
 
@@ -237,11 +693,42 @@ func (s *Store) FileExists(path string) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	fullPath := filepath.Join(s.config.LocalPath, path)
-	_, err := os.Stat(fullPath)
+	if s.tree == nil {
+		return false
+	}
+	_, err := treeFile(s.tree, path)
 	return err == nil
 }
 
+// treeFile looks up a regular file in a tree
+func treeFile(tree *object.Tree, path string) (*object.File, error) {
+	cleaned, err := source.CleanPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := tree.FindEntry(cleaned)
+	if err != nil {
+		return nil, treeError(path, err)
+	}
+	if entry.Mode == filemode.Symlink {
+		return nil, fmt.Errorf("%s: refusing to follow symlink", path)
+	}
+	if !entry.Mode.IsRegular() {
+		return nil, treeError(path, object.ErrFileNotFound)
+	}
+
+	return tree.TreeEntryFile(entry)
+}
+
+// treeError maps go-git lookup failures onto fs.ErrNotExist
+func treeError(path string, err error) error {
+	if errors.Is(err, object.ErrFileNotFound) || errors.Is(err, object.ErrDirectoryNotFound) || errors.Is(err, object.ErrEntryNotFound) {
+		return &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return err
+}
+
 // CurrentCommit returns the current HEAD commit SHA
 func (s *Store) CurrentCommit() string {
 	s.mu.RLock()
@@ -249,6 +736,43 @@ func (s *Store) CurrentCommit() string {
 	return s.currentCommit
 }
 
+// Stale reports whether the last update failed, so the commit being served
+// may be behind upstream
+func (s *Store) Stale() bool {
+	return s.StaleReason() != ""
+}
+
+// StaleReason returns why the last update failed, or "" if it succeeded
+func (s *Store) StaleReason() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.staleReason
+}
+
+// CurrentRevision returns the current HEAD commit SHA
+func (s *Store) CurrentRevision() string {
+	return s.CurrentCommit()
+}
+
+// Refresh pulls upstream changes, retrying transient failures
+func (s *Store) Refresh(ctx context.Context) (bool, error) {
+	return s.PullWithRetry(ctx, 3)
+}
+
+// Info describes the tracked repository
+func (s *Store) Info() source.Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return source.Info{
+		Type:     "git",
+		Location: s.config.RepoURL,
+		Mode:     s.mode(),
+		Ref:      s.ref(),
+		Tag:      s.currentTag.name,
+	}
+}
+
 // RepoURL returns the configured repository URL
 func (s *Store) RepoURL() string {
 	return s.config.RepoURL
@@ -259,8 +783,30 @@ func (s *Store) Branch() string {
 	return s.config.Branch
 }
 
-// WalkFiles walks all files in the repository matching a pattern
-func (s *Store) WalkFiles(dir string, fn func(path string, content []byte) error) error {
+// WebhookRef returns the git ref pattern whose pushes should trigger a sync,
+// or "" when a pinned commit makes webhooks pointless
+func (s *Store) WebhookRef() string {
+	switch s.mode() {
+	case modeCommit:
+		return ""
+	case modeTag:
+		return "refs/tags/" + s.config.TagPattern
+	default:
+		return "refs/heads/" + s.config.Branch
+	}
+}
+
+// CurrentTag returns the tag being served in tag mode
+func (s *Store) CurrentTag() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.currentTag.name
+}
+
+// WalkFiles walks the files under dir in the served commit that match
+// accepts, reading only those. Symlinks are skipped, as ReadFile refuses
+// them.
+func (s *Store) WalkFiles(dir string, match func(path string) bool, fn func(path string, content []byte) error) error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -268,23 +814,8 @@ func (s *Store) WalkFiles(dir string, fn func(path string, content []byte) error
 		return errors.New("repository not initialized")
 	}
 
-	ref, err := s.repo.Head()
-	if err != nil {
-		return fmt.Errorf("failed to get HEAD: %w", err)
-	}
-
-	commit, err := s.repo.CommitObject(ref.Hash())
-	if err != nil {
-		return fmt.Errorf("failed to get commit: %w", err)
-	}
-
-	tree, err := commit.Tree()
-	if err != nil {
-		return fmt.Errorf("failed to get tree: %w", err)
-	}
-
-	return tree.Files().ForEach(func(f *object.File) error {
-		if dir != "" && !strings.HasPrefix(f.Name, dir) {
+	return s.tree.Files().ForEach(func(f *object.File) error {
+		if !f.Mode.IsRegular() || !source.InDir(f.Name, dir) || !match(f.Name) {
 			return nil
 		}
 
@@ -303,23 +834,109 @@ func (s *Store) WalkFiles(dir string, fn func(path string, content []byte) error
 	})
 }
 
-func (s *Store) getAuth(ctx context.Context) (*http.BasicAuth, error) {
-	token, err := s.config.Auth.Token(ctx)
+// FileHistory returns every revision of a file reachable from the given
+// commit, newest first. Commits that delete the file are skipped.
+func (s *Store) FileHistory(revision, path string) ([]source.FileRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.repo == nil {
+		return nil, errors.New("repository not initialized")
+	}
+
+	path, err := source.CleanPath(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return &http.BasicAuth{
-		Username: "x-access-token",
-		Password: token,
-	}, nil
+	iter, err := s.repo.Log(&git.LogOptions{
+		From:       plumbing.NewHash(revision),
+		Order:      git.LogOrderCommitterTime,
+		PathFilter: func(p string) bool { return p == path },
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+	defer iter.Close()
+
+	var revisions []source.FileRevision
+	err = iter.ForEach(func(c *object.Commit) error {
+		f, err := c.File(path)
+		if errors.Is(err, object.ErrFileNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s at %s: %w", path, c.Hash, err)
+		}
+
+		content, err := f.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read %s at %s: %w", path, c.Hash, err)
+		}
+
+		revisions = append(revisions, source.FileRevision{
+			Commit:  c.Hash.String(),
+			Author:  c.Author.Name,
+			Time:    c.Committer.When,
+			Content: []byte(content),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
 }
 
-func (s *Store) updateCurrentCommit() error {
-	ref, err := s.repo.Head()
+var (
+	_ source.Source            = (*Store)(nil)
+	_ source.History           = (*Store)(nil)
+	_ source.RevisionReader    = (*Store)(nil)
+	_ source.ChangeTracker     = (*Store)(nil)
+	_ source.Differ            = (*Store)(nil)
+	_ source.Timeline          = (*Store)(nil)
+	_ source.StaleReporter     = (*Store)(nil)
+	_ source.SignatureReporter = (*Store)(nil)
+)
+
+// LastVerification returns the result of the most recent signature check
+func (s *Store) LastVerification() *source.Verification {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.verification
+}
+
+// verifyCommit checks a commit's signature if a verifier is configured;
+// callers must hold the write lock
+func (s *Store) verifyCommit(hash plumbing.Hash) error {
+	if s.config.Verifier == nil {
+		return nil
+	}
+
+	commit, err := s.repo.CommitObject(hash)
 	if err != nil {
+		return fmt.Errorf("failed to read commit %s: %w", hash, err)
+	}
+
+	result, err := s.config.Verifier.Verify(commit)
+	s.verification = result
+	if err != nil {
+		s.logger.Error("commit signature verification failed",
+			"commit", hash.String(),
+			"error", err,
+		)
 		return err
 	}
-	s.currentCommit = ref.Hash().String()
+
+	s.logger.Info("commit signature verified",
+		"commit", hash.String(),
+		"method", result.Method,
+		"signer", result.Signer,
+	)
 	return nil
 }
+
+func (s *Store) getAuth(ctx context.Context) (transport.AuthMethod, error) {
+	return s.config.Auth.AuthMethod(ctx)
+}
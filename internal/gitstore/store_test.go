@@ -0,0 +1,251 @@
+package gitstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+func TestCloneRestartWithoutUpstream(t *testing.T) {
+	u := newUpstream(t)
+	first := u.commit(map[string]string{"index.yaml": "version: 1\n"})
+	u.tag("v1.0.0", first)
+	head := u.commit(map[string]string{"index.yaml": "version: 2\n"})
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		want     plumbing.Hash
+		wantTag  string
+		wantFile string
+	}{
+		{name: "branch", want: head, wantFile: "version: 2\n"},
+		{name: "tag", cfg: Config{TagPattern: "v*"}, want: first, wantTag: "v1.0.0", wantFile: "version: 1\n"},
+		{name: "pinned commit", cfg: Config{Commit: first.String()}, want: first, wantFile: "version: 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.LocalPath = filepath.Join(t.TempDir(), "clone")
+			u.mustClone(tt.cfg)
+
+			restore := u.disconnect()
+			defer restore()
+
+			s := u.mustClone(tt.cfg)
+			if got := s.CurrentCommit(); got != tt.want.String() {
+				t.Errorf("serving %s after restart, want %s", got, tt.want)
+			}
+			if got := s.CurrentTag(); got != tt.wantTag {
+				t.Errorf("serving tag %q after restart, want %q", got, tt.wantTag)
+			}
+			if !s.Stale() || s.StaleReason() != staleFetchFailed {
+				t.Errorf("Stale() = %v, StaleReason() = %q; want %q", s.Stale(), s.StaleReason(), staleFetchFailed)
+			}
+			content, err := s.ReadFile("index.yaml")
+			if err != nil || string(content) != tt.wantFile {
+				t.Errorf("ReadFile(index.yaml) = %q, %v; want %q", content, err, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestCloneRestartMissingPin(t *testing.T) {
+	u := newUpstream(t)
+	first := u.commit(map[string]string{"index.yaml": "version: 1\n"})
+	localPath := filepath.Join(t.TempDir(), "clone")
+	u.mustClone(Config{LocalPath: localPath, Commit: first.String()})
+
+	// The new pin was committed after the clone, which cannot fetch it
+	pinned := u.commit(map[string]string{"index.yaml": "version: 2\n"})
+	restore := u.disconnect()
+	defer restore()
+
+	if _, err := u.clone(Config{LocalPath: localPath, Commit: pinned.String()}); err == nil {
+		t.Fatal("Clone succeeded without the pinned commit, want error")
+	}
+}
+
+func TestPullStaleReason(t *testing.T) {
+	u := newUpstream(t)
+	first := u.commit(map[string]string{"index.yaml": "version: 1\n"})
+	s := u.mustClone(Config{LocalPath: filepath.Join(t.TempDir(), "clone")})
+	ctx := context.Background()
+
+	if s.Stale() {
+		t.Fatalf("fresh clone is stale: %s", s.StaleReason())
+	}
+
+	next := u.commit(map[string]string{"index.yaml": "version: 2\n"})
+	restore := u.disconnect()
+	if changed, err := s.Pull(ctx); err == nil || changed {
+		t.Fatalf("Pull without upstream = %v, %v; want error", changed, err)
+	}
+	if s.StaleReason() != staleFetchFailed {
+		t.Errorf("StaleReason() = %q, want %q", s.StaleReason(), staleFetchFailed)
+	}
+	if s.CurrentCommit() != first.String() {
+		t.Errorf("serving %s while stale, want %s", s.CurrentCommit(), first)
+	}
+
+	restore()
+	changed, err := s.Pull(ctx)
+	if err != nil || !changed {
+		t.Fatalf("Pull after reconnecting = %v, %v; want a change", changed, err)
+	}
+	if s.Stale() {
+		t.Errorf("still stale after a successful pull: %s", s.StaleReason())
+	}
+	if s.CurrentCommit() != next.String() {
+		t.Errorf("serving %s after reconnecting, want %s", s.CurrentCommit(), next)
+	}
+}
+
+func TestPullUnresolvedTarget(t *testing.T) {
+	u := newUpstream(t)
+	first := u.commit(map[string]string{"index.yaml": "version: 1\n"})
+	u.tag("v1.0.0", first)
+	s := u.mustClone(Config{InMemory: true, TagPattern: "v*"})
+
+	// Deleting the only matching tag leaves nothing to resolve after a fetch
+	if err := u.repo.DeleteTag("v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.repo.DeleteTag("v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Pull(context.Background()); err == nil {
+		t.Fatal("Pull with no matching tag succeeded, want error")
+	}
+	if s.StaleReason() != staleUnresolved {
+		t.Errorf("StaleReason() = %q, want %q", s.StaleReason(), staleUnresolved)
+	}
+	if s.CurrentCommit() != first.String() {
+		t.Errorf("serving %s, want %s", s.CurrentCommit(), first)
+	}
+}
+
+// countingAuth hands out anonymous credentials, or err once set, and
+// counts how often it was asked
+type countingAuth struct {
+	calls int
+	err   error
+}
+
+func (a *countingAuth) AuthMethod(context.Context) (transport.AuthMethod, error) {
+	a.calls++
+	return nil, a.err
+}
+
+func TestPullAuthProvider(t *testing.T) {
+	u := newUpstream(t)
+	u.commit(map[string]string{"index.yaml": "version: 1\n"})
+	auth := &countingAuth{}
+	s := u.mustClone(Config{InMemory: true, Auth: auth})
+	ctx := context.Background()
+
+	// Credentials are requested for every remote operation, so providers
+	// can hand out short-lived tokens
+	if _, err := s.Pull(ctx); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if auth.calls != 2 {
+		t.Errorf("provider asked %d times for a clone and a pull, want 2", auth.calls)
+	}
+
+	auth.err = errors.New("token expired")
+	if _, err := s.Pull(ctx); err == nil {
+		t.Fatal("Pull succeeded without credentials, want error")
+	}
+	if s.StaleReason() != staleAuthFailed {
+		t.Errorf("StaleReason() = %q, want %q", s.StaleReason(), staleAuthFailed)
+	}
+}
+
+func TestWebhookRef(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{name: "default branch", want: "refs/heads/main"},
+		{name: "branch", cfg: Config{Branch: "release"}, want: "refs/heads/release"},
+		{name: "tag pattern", cfg: Config{TagPattern: "v*"}, want: "refs/tags/v*"},
+		{name: "pinned commit", cfg: Config{Commit: "0123456789abcdef0123456789abcdef01234567"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.RepoURL = "https://github.com/example/registry.git"
+			tt.cfg.InMemory = true
+			s, err := New(tt.cfg)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if got := s.WebhookRef(); got != tt.want {
+				t.Errorf("WebhookRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInMemoryStore(t *testing.T) {
+	u := newUpstream(t)
+	first := u.commit(map[string]string{
+		"index.yaml":     "version: 1\n",
+		"servers/a.yaml": "name: a\n",
+		"servers/b.json": `{"name":"b"}`,
+		"README.md":      "registry\n",
+	})
+
+	// Nothing but the upstream exists on disk, and the store needs no path
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+	s := u.mustClone(Config{InMemory: true})
+	if entries, _ := os.ReadDir(cwd); len(entries) != 0 {
+		t.Errorf("in-memory clone wrote %d entries to the working directory", len(entries))
+	}
+	if s.worktree != nil {
+		t.Error("in-memory clone has a worktree")
+	}
+
+	content, err := s.ReadFile("servers/a.yaml")
+	if err != nil || string(content) != "name: a\n" {
+		t.Errorf("ReadFile(servers/a.yaml) = %q, %v", content, err)
+	}
+	if _, err := s.ReadFile("../index.yaml"); err == nil {
+		t.Error("ReadFile read outside the repository")
+	}
+
+	files, err := s.ListFiles("servers")
+	if err != nil || !slices.Equal(files, []string{"a.yaml", "b.json"}) {
+		t.Errorf("ListFiles(servers) = %v, %v; want the regular files", files, err)
+	}
+
+	var walked []string
+	err = s.WalkFiles("servers", func(p string) bool { return filepath.Ext(p) == ".yaml" }, func(p string, _ []byte) error {
+		walked = append(walked, p)
+		return nil
+	})
+	if err != nil || !slices.Equal(walked, []string{"servers/a.yaml"}) {
+		t.Errorf("WalkFiles(servers) visited %v, %v", walked, err)
+	}
+
+	u.commit(map[string]string{"servers/a.yaml": "name: a\nversion: 2\n"})
+	if changed, err := s.Pull(context.Background()); err != nil || !changed {
+		t.Fatalf("Pull = %v, %v; want a change", changed, err)
+	}
+	content, err = s.ReadFile("servers/a.yaml")
+	if err != nil || string(content) != "name: a\nversion: 2\n" {
+		t.Errorf("ReadFile after pull = %q, %v", content, err)
+	}
+	content, err = s.ReadFileAt(first.String(), "servers/a.yaml")
+	if err != nil || string(content) != "name: a\n" {
+		t.Errorf("ReadFileAt(first) = %q, %v", content, err)
+	}
+}
@@ -0,0 +1,127 @@
+package gitstore
+
+import (
+	"strconv"
+	"strings"
+)
+
+// version is a parsed semantic version tag name
+type version struct {
+	core       [3]uint64
+	prerelease []string
+}
+
+// parseVersion parses a tag name of the form [v]MAJOR.MINOR.PATCH with an
+// optional -prerelease and +build suffix. Build metadata is ignored.
+func parseVersion(name string) (version, bool) {
+	s := strings.TrimPrefix(name, "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, hasPre := strings.Cut(s, "-")
+
+	var v version
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return version{}, false
+	}
+	for i, p := range parts {
+		n, ok := numeric(p)
+		if !ok {
+			return version{}, false
+		}
+		v.core[i] = n
+	}
+	if hasPre {
+		v.prerelease = strings.Split(pre, ".")
+		for _, id := range v.prerelease {
+			if id == "" {
+				return version{}, false
+			}
+		}
+	}
+	return v, true
+}
+
+// compare orders two versions by semver precedence: -1, 0 or 1
+func (v version) compare(o version) int {
+	for i := range v.core {
+		if v.core[i] != o.core[i] {
+			if v.core[i] < o.core[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// A release ranks above its prereleases
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		if c := compareIdentifier(v.prerelease[i], o.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.prerelease) < len(o.prerelease):
+		return -1
+	case len(v.prerelease) > len(o.prerelease):
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier orders prerelease identifiers: numeric ones by value
+// and below alphanumeric ones, which order lexically
+func compareIdentifier(a, b string) int {
+	an, aNum := numeric(a)
+	bn, bNum := numeric(b)
+	switch {
+	case aNum && bNum:
+		if an == bn {
+			return 0
+		}
+		if an < bn {
+			return -1
+		}
+		return 1
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// numeric parses a semver numeric identifier, which has no leading zeros
+func numeric(s string) (uint64, bool) {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	return n, err == nil
+}
+
+// newerTag reports whether tag a orders after tag b. Tags named as semantic
+// versions order by precedence and above all other tags, which order by
+// when they were tagged.
+func newerTag(a, b taggedCommit) bool {
+	av, aOK := parseVersion(a.name)
+	bv, bOK := parseVersion(b.name)
+	switch {
+	case aOK && bOK:
+		if c := av.compare(bv); c != 0 {
+			return c > 0
+		}
+	case aOK != bOK:
+		return aOK
+	}
+	if !a.when.Equal(b.when) {
+		return a.when.After(b.when)
+	}
+	return a.name > b.name
+}
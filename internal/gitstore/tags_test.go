@@ -0,0 +1,225 @@
+package gitstore
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{name: "v1.2.3", ok: true},
+		{name: "1.2.3", ok: true},
+		{name: "v1.2.3-rc.1", ok: true},
+		{name: "v1.2.3+build.5", ok: true},
+		{name: "v1.2.3-beta+build", ok: true},
+		{name: "v1.2", ok: false},
+		{name: "v1.2.3.4", ok: false},
+		{name: "v01.2.3", ok: false},
+		{name: "v1.2.x", ok: false},
+		{name: "v1.2.3-", ok: false},
+		{name: "v1.2.3-rc..1", ok: false},
+		{name: "release-2026-01", ok: false},
+		{name: "latest", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := parseVersion(tt.name); ok != tt.ok {
+				t.Errorf("parseVersion(%q) ok = %v, want %v", tt.name, ok, tt.ok)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	// Each version orders before the next, as in the semver spec
+	ordered := []string{
+		"v0.9.9",
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v1.2.0",
+		"v1.10.0",
+		"v2.0.0",
+	}
+
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := parseVersion(ordered[i])
+		b, _ := parseVersion(ordered[i+1])
+		if got := a.compare(b); got != -1 {
+			t.Errorf("%s compare %s = %d, want -1", ordered[i], ordered[i+1], got)
+		}
+		if got := b.compare(a); got != 1 {
+			t.Errorf("%s compare %s = %d, want 1", ordered[i+1], ordered[i], got)
+		}
+	}
+
+	a, _ := parseVersion("v1.0.0+build.1")
+	b, _ := parseVersion("1.0.0+build.2")
+	if got := a.compare(b); got != 0 {
+		t.Errorf("build metadata compare = %d, want 0", got)
+	}
+}
+
+func TestNewerTag(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name string
+		tags []taggedCommit
+		want []string // newest first
+	}{
+		{
+			name: "semver ignores tag time",
+			tags: []taggedCommit{
+				{name: "v1.9.0", when: day(5)},
+				{name: "v1.10.0", when: day(1)},
+				{name: "v1.10.0-rc.1", when: day(3)},
+			},
+			want: []string{"v1.10.0", "v1.10.0-rc.1", "v1.9.0"},
+		},
+		{
+			name: "other tags by time",
+			tags: []taggedCommit{
+				{name: "release-a", when: day(1)},
+				{name: "release-c", when: day(3)},
+				{name: "release-b", when: day(2)},
+			},
+			want: []string{"release-c", "release-b", "release-a"},
+		},
+		{
+			name: "versions rank above other tags",
+			tags: []taggedCommit{
+				{name: "latest", when: day(9)},
+				{name: "v1.0.0", when: day(1)},
+				{name: "v0.9.0", when: day(2)},
+			},
+			want: []string{"v1.0.0", "v0.9.0", "latest"},
+		},
+		{
+			name: "equal time by name",
+			tags: []taggedCommit{
+				{name: "release-a", when: day(1)},
+				{name: "release-b", when: day(1)},
+			},
+			want: []string{"release-b", "release-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := append([]taggedCommit(nil), tt.tags...)
+			sort.Slice(tags, func(i, j int) bool { return newerTag(tags[i], tags[j]) })
+
+			for i, tag := range tags {
+				if tag.name != tt.want[i] {
+					t.Fatalf("order = %v, want %v", names(tags), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPullTagMode(t *testing.T) {
+	u := newUpstream(t)
+	oldest := u.commit(map[string]string{"index.yaml": "version: 1\n"})
+	middle := u.commit(map[string]string{"index.yaml": "version: 2\n"})
+	newest := u.commit(map[string]string{"index.yaml": "version: 3\n"})
+	u.tag("v1.9.0", newest)
+	u.tag("v1.10.0", oldest)
+
+	s := u.mustClone(Config{InMemory: true, TagPattern: "v*"})
+	if s.CurrentTag() != "v1.10.0" || s.CurrentCommit() != oldest.String() {
+		t.Fatalf("cloned %s at %s, want v1.10.0 at %s", s.CurrentTag(), s.CurrentCommit(), oldest)
+	}
+
+	steps := []struct {
+		name       string
+		push       func()
+		wantTag    string
+		wantCommit plumbing.Hash
+	}{
+		{
+			name:       "prerelease of a later version",
+			push:       func() { u.tag("v1.11.0-rc.1", middle) },
+			wantTag:    "v1.11.0-rc.1",
+			wantCommit: middle,
+		},
+		{
+			name:       "release on an older commit",
+			push:       func() { u.tag("v1.11.0", oldest) },
+			wantTag:    "v1.11.0",
+			wantCommit: oldest,
+		},
+		{
+			name:       "lower version on a newer commit",
+			push:       func() { u.tag("v1.10.1", newest) },
+			wantTag:    "v1.11.0",
+			wantCommit: oldest,
+		},
+		{
+			name:       "newer tag that is not a version",
+			push:       func() { u.annotatedTag("v-nightly", newest) },
+			wantTag:    "v1.11.0",
+			wantCommit: oldest,
+		},
+	}
+	for _, step := range steps {
+		step.push()
+		if _, err := s.Pull(context.Background()); err != nil {
+			t.Fatalf("%s: Pull: %v", step.name, err)
+		}
+		if s.CurrentTag() != step.wantTag || s.CurrentCommit() != step.wantCommit.String() {
+			t.Errorf("%s: serving %s at %s, want %s at %s",
+				step.name, s.CurrentTag(), s.CurrentCommit(), step.wantTag, step.wantCommit)
+		}
+	}
+}
+
+func TestPullMovedTag(t *testing.T) {
+	u := newUpstream(t)
+	oldest := u.commit(map[string]string{"index.yaml": "version: 1\n"})
+	middle := u.commit(map[string]string{"index.yaml": "version: 2\n"})
+	newest := u.commit(map[string]string{"index.yaml": "version: 3\n"})
+	u.tag("release", oldest)
+
+	s := u.mustClone(Config{InMemory: true, TagPattern: "release*"})
+
+	steps := []struct {
+		name       string
+		moveTo     plumbing.Hash
+		wantCommit plumbing.Hash
+	}{
+		{name: "moved forward", moveTo: newest, wantCommit: newest},
+		{name: "moved back", moveTo: middle, wantCommit: newest},
+	}
+	for _, step := range steps {
+		u.tag("release", step.moveTo)
+		if _, err := s.Pull(context.Background()); err != nil {
+			t.Fatalf("%s: Pull: %v", step.name, err)
+		}
+		if s.CurrentCommit() != step.wantCommit.String() {
+			t.Errorf("%s: serving %s, want %s", step.name, s.CurrentCommit(), step.wantCommit)
+		}
+	}
+}
+
+func names(tags []taggedCommit) []string {
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = t.name
+	}
+	return out
+}
@@ -0,0 +1,285 @@
+package gitstore
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// ErrUnverifiedCommit is returned when a commit fails signature verification
+var ErrUnverifiedCommit = errors.New("commit signature verification failed")
+
+// Verifier checks that a commit carries a trusted signature before it is served
+type Verifier interface {
+	Verify(c *object.Commit) (*source.Verification, error)
+}
+
+// SignatureVerifier accepts commits signed by a key in a GPG keyring or an
+// SSH allowed-signers file
+type SignatureVerifier struct {
+	keyring        string
+	allowedSigners []allowedSigner
+}
+
+type allowedSigner struct {
+	principals []string
+	key        ssh.PublicKey
+}
+
+const sshSignatureArmorStart = "-----BEGIN SSH SIGNATURE-----"
+
+// NewSignatureVerifier loads an armored GPG keyring and/or an SSH
+// allowed-signers file. At least one must be given.
+func NewSignatureVerifier(keyringPath, allowedSignersPath string) (*SignatureVerifier, error) {
+	if keyringPath == "" && allowedSignersPath == "" {
+		return nil, errors.New("a GPG keyring or SSH allowed signers file is required")
+	}
+
+	v := &SignatureVerifier{}
+
+	if keyringPath != "" {
+		keyring, err := os.ReadFile(keyringPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GPG keyring: %w", err)
+		}
+		v.keyring = string(keyring)
+	}
+
+	if allowedSignersPath != "" {
+		content, err := os.ReadFile(allowedSignersPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read allowed signers file: %w", err)
+		}
+		signers, err := parseAllowedSigners(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse allowed signers file: %w", err)
+		}
+		v.allowedSigners = signers
+	}
+
+	return v, nil
+}
+
+// Verify checks the commit's GPG or SSH signature
+func (v *SignatureVerifier) Verify(c *object.Commit) (*source.Verification, error) {
+	result := &source.Verification{
+		Commit:    c.Hash.String(),
+		CheckedAt: time.Now(),
+	}
+
+	fail := func(err error) (*source.Verification, error) {
+		result.Error = err.Error()
+		return result, fmt.Errorf("%w: %s: %v", ErrUnverifiedCommit, c.Hash, err)
+	}
+
+	if c.PGPSignature == "" {
+		return fail(errors.New("commit is not signed"))
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(c.PGPSignature), sshSignatureArmorStart) {
+		result.Method = "ssh"
+		if len(v.allowedSigners) == 0 {
+			return fail(errors.New("SSH signature but no allowed signers configured"))
+		}
+
+		message, err := encodeWithoutSignature(c)
+		if err != nil {
+			return fail(err)
+		}
+
+		signer, err := v.verifySSH(c.PGPSignature, message)
+		if err != nil {
+			return fail(err)
+		}
+		result.Signer = signer
+	} else {
+		result.Method = "gpg"
+		if v.keyring == "" {
+			return fail(errors.New("GPG signature but no keyring configured"))
+		}
+
+		entity, err := c.Verify(v.keyring)
+		if err != nil {
+			return fail(err)
+		}
+		if id := entity.PrimaryIdentity(); id != nil {
+			result.Signer = id.Name
+		} else {
+			result.Signer = entity.PrimaryKey.KeyIdString()
+		}
+	}
+
+	result.Verified = true
+	return result, nil
+}
+
+// verifySSH checks an armored SSHSIG signature in the "git" namespace and
+// returns the principal of the matching allowed signer
+func (v *SignatureVerifier) verifySSH(armored string, message []byte) (string, error) {
+	blob, err := decodeSSHArmor(armored)
+	if err != nil {
+		return "", err
+	}
+
+	if !bytes.HasPrefix(blob, []byte("SSHSIG")) {
+		return "", errors.New("missing SSHSIG magic")
+	}
+
+	var sig struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}
+	if err := ssh.Unmarshal(blob[len("SSHSIG"):], &sig); err != nil {
+		return "", fmt.Errorf("malformed SSH signature: %w", err)
+	}
+	if sig.Version != 1 {
+		return "", fmt.Errorf("unsupported SSH signature version %d", sig.Version)
+	}
+	if sig.Namespace != "git" {
+		return "", fmt.Errorf("SSH signature namespace %q, want \"git\"", sig.Namespace)
+	}
+
+	pub, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid signing key: %w", err)
+	}
+
+	var signature ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &signature); err != nil {
+		return "", fmt.Errorf("malformed SSH signature blob: %w", err)
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return "", fmt.Errorf("unsupported hash algorithm %q", sig.HashAlgorithm)
+	}
+	h.Write(message)
+
+	signed := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{sig.Namespace, sig.Reserved, sig.HashAlgorithm, h.Sum(nil)})...)
+
+	if err := pub.Verify(signed, &signature); err != nil {
+		return "", fmt.Errorf("bad SSH signature: %w", err)
+	}
+
+	for _, s := range v.allowedSigners {
+		if bytes.Equal(s.key.Marshal(), pub.Marshal()) {
+			return strings.Join(s.principals, ","), nil
+		}
+	}
+
+	return "", fmt.Errorf("signing key %s is not an allowed signer", ssh.FingerprintSHA256(pub))
+}
+
+// parseAllowedSigners reads the ssh-keygen allowed_signers format:
+// principals [options] keytype base64-key [comment]
+func parseAllowedSigners(content []byte) ([]allowedSigner, error) {
+	var signers []allowedSigner
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		principals, rest, ok := strings.Cut(text, " ")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing key", line)
+		}
+
+		key, _, options, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(rest)))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if !allowsGitNamespace(options) {
+			continue
+		}
+
+		signers = append(signers, allowedSigner{
+			principals: strings.Split(strings.Trim(principals, `"`), ","),
+			key:        key,
+		})
+	}
+
+	return signers, scanner.Err()
+}
+
+// allowsGitNamespace reports whether a signer's namespaces option, if any,
+// permits git signatures
+func allowsGitNamespace(options []string) bool {
+	for _, opt := range options {
+		name, value, ok := strings.Cut(opt, "=")
+		if !ok || !strings.EqualFold(name, "namespaces") {
+			continue
+		}
+		for _, ns := range strings.Split(strings.Trim(value, `"`), ",") {
+			if ns == "git" || ns == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func decodeSSHArmor(armored string) ([]byte, error) {
+	var b64 strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(armored), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "-----") {
+			continue
+		}
+		b64.WriteString(line)
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(b64.String())
+	if err != nil {
+		return nil, fmt.Errorf("malformed SSH signature armor: %w", err)
+	}
+	return blob, nil
+}
+
+// encodeWithoutSignature returns the raw commit object bytes that were signed
+func encodeWithoutSignature(c *object.Commit) ([]byte, error) {
+	obj := &plumbing.MemoryObject{}
+	if err := c.EncodeWithoutSignature(obj); err != nil {
+		return nil, fmt.Errorf("failed to encode commit: %w", err)
+	}
+
+	reader, err := obj.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,308 @@
+package gitstore
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshSigner signs commits the way git does with gpg.format=ssh
+type sshSigner struct {
+	signer ssh.Signer
+}
+
+func newSSHSigner(t *testing.T) sshSigner {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sshSigner{signer: signer}
+}
+
+// allowedSigners returns an allowed_signers line for the key
+func (s sshSigner) allowedSigners(principal, options string) string {
+	line := principal + " "
+	if options != "" {
+		line += options + " "
+	}
+	return line + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(s.signer.PublicKey())))
+}
+
+// sign returns an armored SSHSIG signature of message in a namespace
+func (s sshSigner) sign(t *testing.T, namespace string, message []byte) string {
+	t.Helper()
+	sum := sha512.Sum512(message)
+	signed := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{namespace, "", "sha512", sum[:]})...)
+
+	sig, err := s.signer.Sign(rand.Reader, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{1, s.signer.PublicKey().Marshal(), namespace, "", "sha512", ssh.Marshal(sig)})...)
+
+	return sshSignatureArmorStart + "\n" + base64.StdEncoding.EncodeToString(blob) + "\n-----END SSH SIGNATURE-----\n"
+}
+
+// commitSigner signs fixture commits with an SSH key
+type commitSigner struct {
+	t      *testing.T
+	signer sshSigner
+}
+
+func (c commitSigner) Sign(message io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(message)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(c.signer.sign(c.t, "git", content)), nil
+}
+
+func testCommit(message string) *object.Commit {
+	who := object.Signature{Name: "Registry Bot", Email: "bot@example.com", When: time.Unix(1767225600, 0).UTC()}
+	return &object.Commit{Author: who, Committer: who, Message: message}
+}
+
+func TestSignatureVerifierSSH(t *testing.T) {
+	trusted := newSSHSigner(t)
+	untrusted := newSSHSigner(t)
+
+	tests := []struct {
+		name       string
+		signers    string
+		sign       func(c *object.Commit, message []byte) string
+		wantSigner string
+		wantErr    bool
+	}{
+		{
+			name:    "trusted key",
+			signers: trusted.allowedSigners("release@example.com", ""),
+			sign: func(_ *object.Commit, m []byte) string {
+				return trusted.sign(t, "git", m)
+			},
+			wantSigner: "release@example.com",
+		},
+		{
+			name:    "several principals",
+			signers: trusted.allowedSigners(`"a@example.com,b@example.com"`, `namespaces="git"`),
+			sign: func(_ *object.Commit, m []byte) string {
+				return trusted.sign(t, "git", m)
+			},
+			wantSigner: "a@example.com,b@example.com",
+		},
+		{
+			name:    "unsigned",
+			signers: trusted.allowedSigners("release@example.com", ""),
+			sign:    func(*object.Commit, []byte) string { return "" },
+			wantErr: true,
+		},
+		{
+			name:    "key not allowed",
+			signers: trusted.allowedSigners("release@example.com", ""),
+			sign: func(_ *object.Commit, m []byte) string {
+				return untrusted.sign(t, "git", m)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "key not allowed for git",
+			signers: trusted.allowedSigners("release@example.com", `namespaces="file"`),
+			sign: func(_ *object.Commit, m []byte) string {
+				return trusted.sign(t, "git", m)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "other namespace",
+			signers: trusted.allowedSigners("release@example.com", ""),
+			sign: func(_ *object.Commit, m []byte) string {
+				return trusted.sign(t, "file", m)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "commit changed after signing",
+			signers: trusted.allowedSigners("release@example.com", ""),
+			sign: func(c *object.Commit, m []byte) string {
+				sig := trusted.sign(t, "git", m)
+				c.Message += "tampered\n"
+				return sig
+			},
+			wantErr: true,
+		},
+		{
+			name:    "malformed armor",
+			signers: trusted.allowedSigners("release@example.com", ""),
+			sign: func(*object.Commit, []byte) string {
+				return sshSignatureArmorStart + "\nnot base64!\n-----END SSH SIGNATURE-----\n"
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signers, err := parseAllowedSigners([]byte(tt.signers))
+			if err != nil {
+				t.Fatalf("parseAllowedSigners() error = %v", err)
+			}
+			v := &SignatureVerifier{allowedSigners: signers}
+
+			c := testCommit("Update servers\n")
+			message, err := encodeWithoutSignature(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.PGPSignature = tt.sign(c, message)
+
+			result, err := v.Verify(c)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnverifiedCommit) {
+					t.Fatalf("Verify() error = %v, want ErrUnverifiedCommit", err)
+				}
+				if result.Verified || result.Error == "" {
+					t.Errorf("Verify() result = %+v, want an unverified result with an error", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if !result.Verified || result.Method != "ssh" || result.Signer != tt.wantSigner {
+				t.Errorf("Verify() result = %+v, want verified ssh signature by %s", result, tt.wantSigner)
+			}
+		})
+	}
+}
+
+func TestSignatureVerifierMissingTrust(t *testing.T) {
+	signer := newSSHSigner(t)
+	c := testCommit("Update servers\n")
+	message, err := encodeWithoutSignature(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "ssh signature without allowed signers", signature: signer.sign(t, "git", message)},
+		{name: "gpg signature without keyring", signature: "-----BEGIN PGP SIGNATURE-----\n\n-----END PGP SIGNATURE-----\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.PGPSignature = tt.signature
+			if _, err := (&SignatureVerifier{}).Verify(c); !errors.Is(err, ErrUnverifiedCommit) {
+				t.Errorf("Verify() error = %v, want ErrUnverifiedCommit", err)
+			}
+		})
+	}
+}
+
+func TestParseAllowedSigners(t *testing.T) {
+	key := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(newSSHSigner(t).signer.PublicKey())))
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", content: ""},
+		{name: "comments and blank lines", content: "# signers\n\n"},
+		{name: "one signer", content: "a@example.com " + key + "\n", want: 1},
+		{name: "git namespace", content: `a@example.com namespaces="git,file" ` + key, want: 1},
+		{name: "any namespace", content: `a@example.com namespaces="*" ` + key, want: 1},
+		{name: "other namespace is skipped", content: `a@example.com namespaces="file" ` + key},
+		{name: "missing key", content: "a@example.com", wantErr: true},
+		{name: "invalid key", content: "a@example.com ssh-ed25519 AAAA", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signers, err := parseAllowedSigners([]byte(tt.content))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseAllowedSigners() = %d signers, want an error", len(signers))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAllowedSigners() error = %v", err)
+			}
+			if len(signers) != tt.want {
+				t.Errorf("parseAllowedSigners() = %d signers, want %d", len(signers), tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreVerifiesCommits(t *testing.T) {
+	trusted := newSSHSigner(t)
+	allowed := filepath.Join(t.TempDir(), "allowed_signers")
+	if err := os.WriteFile(allowed, []byte(trusted.allowedSigners("bot@example.com", "")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := NewSignatureVerifier("", allowed)
+	if err != nil {
+		t.Fatalf("NewSignatureVerifier: %v", err)
+	}
+
+	u := newUpstream(t)
+	u.signer = commitSigner{t: t, signer: trusted}
+	signed := u.commit(map[string]string{"index.yaml": "version: 1\n"})
+
+	s := u.mustClone(Config{InMemory: true, Verifier: verifier})
+	if v := s.LastVerification(); v == nil || !v.Verified || v.Commit != signed.String() {
+		t.Fatalf("LastVerification() = %+v, want %s verified", v, signed)
+	}
+
+	u.signer = nil
+	u.commit(map[string]string{"index.yaml": "version: 2\n"})
+
+	changed, err := s.Pull(context.Background())
+	if !errors.Is(err, ErrUnverifiedCommit) || changed {
+		t.Fatalf("Pull of an unsigned commit = %v, %v; want ErrUnverifiedCommit", changed, err)
+	}
+	if s.CurrentCommit() != signed.String() {
+		t.Errorf("serving %s, want the signed commit %s", s.CurrentCommit(), signed)
+	}
+	if s.StaleReason() != staleUnverified {
+		t.Errorf("StaleReason() = %q, want %q", s.StaleReason(), staleUnverified)
+	}
+
+	// Nothing verified to fall back to on a fresh clone
+	if _, err := u.clone(Config{InMemory: true, Verifier: verifier}); !errors.Is(err, ErrUnverifiedCommit) {
+		t.Errorf("Clone of an unsigned head = %v, want ErrUnverifiedCommit", err)
+	}
+}
@@ -0,0 +1,248 @@
+package localstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// Store serves a registry from a plain directory on the local filesystem
+type Store struct {
+	config   Config
+	root     string
+	revision string
+	mu       sync.RWMutex
+	logger   *slog.Logger
+}
+
+// Config holds local store configuration
+type Config struct {
+	Path         string
+	PollInterval time.Duration
+	Logger       *slog.Logger
+}
+
+var _ source.Source = (*Store)(nil)
+
+// New creates a local store rooted at an existing directory
+func New(cfg Config) (*Store, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("local path is required")
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	root, err := filepath.Abs(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local path: %w", err)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat local path: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("local path is not a directory: %s", root)
+	}
+
+	s := &Store{
+		config: cfg,
+		root:   root,
+		logger: cfg.Logger,
+	}
+
+	revision, err := s.fingerprint()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan local path: %w", err)
+	}
+	s.revision = revision
+
+	return s, nil
+}
+
+// ReadFile reads a file relative to the root directory. Paths and
+// symlinks that resolve outside the root are refused.
+func (s *Store) ReadFile(path string) ([]byte, error) {
+	f, err := s.open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// ListFiles returns all files in a directory
+func (s *Store) ListFiles(dir string) ([]string, error) {
+	f, err := s.open(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	defer f.Close()
+
+	entries, err := f.ReadDir(-1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			files = append(files, entry.Name())
+		}
+	}
+
+	return files, nil
+}
+
+// open opens a path confined to the root directory
+func (s *Store) open(path string) (*os.File, error) {
+	cleaned, err := source.CleanPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.OpenInRoot(s.root, filepath.FromSlash(cleaned))
+}
+
+// WalkFiles walks the files under dir that match accepts, skipping version
+// control metadata and reading only matched files
+func (s *Store) WalkFiles(dir string, match func(path string) bool, fn func(path string, content []byte) error) error {
+	return s.walk(func(rel string, _ fs.FileInfo) error {
+		if !source.InDir(rel, dir) || !match(rel) {
+			return nil
+		}
+
+		content, err := s.ReadFile(rel)
+		if err != nil {
+			return err
+		}
+
+		return fn(rel, content)
+	})
+}
+
+// CurrentRevision returns a fingerprint of the directory contents as of the last refresh
+func (s *Store) CurrentRevision() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.revision
+}
+
+// Refresh rescans the directory and reports whether anything changed
+func (s *Store) Refresh(_ context.Context) (bool, error) {
+	revision, err := s.fingerprint()
+	if err != nil {
+		return false, fmt.Errorf("failed to scan local path: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if revision == s.revision {
+		return false, nil
+	}
+
+	s.logger.Info("local registry updated",
+		"old_revision", s.revision,
+		"new_revision", revision,
+	)
+	s.revision = revision
+	return true, nil
+}
+
+// Info describes the watched directory
+func (s *Store) Info() source.Info {
+	return source.Info{
+		Type:     "local",
+		Location: s.root,
+	}
+}
+
+// Watch polls the directory and calls onChange whenever its contents
+// differ from the current revision. It blocks until ctx is cancelled.
+func (s *Store) Watch(ctx context.Context, onChange func()) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("watching local registry",
+		"path", s.root,
+		"poll_interval", s.config.PollInterval,
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			revision, err := s.fingerprint()
+			if err != nil {
+				s.logger.Warn("failed to scan local registry", "error", err)
+				continue
+			}
+			if revision != s.CurrentRevision() {
+				s.logger.Debug("local registry change detected", "revision", revision)
+				onChange()
+			}
+		}
+	}
+}
+
+// fingerprint hashes the path, size and modification time of every file
+func (s *Store) fingerprint() (string, error) {
+	h := sha256.New()
+	err := s.walk(func(rel string, info fs.FileInfo) error {
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", rel, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// walk visits every regular file under the root in lexical order,
+// passing slash-separated paths relative to the root
+func (s *Store) walk(fn func(rel string, info fs.FileInfo) error) error {
+	return filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+
+		return fn(filepath.ToSlash(rel), info)
+	})
+}
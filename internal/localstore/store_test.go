@@ -0,0 +1,170 @@
+package localstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newStore(t *testing.T, dir string) *Store {
+	t.Helper()
+	s, err := New(Config{Path: dir})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestReadFile(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "registry")
+	writeFile(t, root, "index.yaml", "servers: []\n")
+	writeFile(t, root, "servers/a.yaml", "name: a\n")
+	writeFile(t, parent, "secret.txt", "secret\n")
+	if err := os.Symlink("servers/a.yaml", filepath.Join(root, "alias.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(parent, "secret.txt"), filepath.Join(root, "escape.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("..", filepath.Join(root, "servers", "up")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newStore(t, root)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{name: "file", path: "index.yaml", want: "servers: []\n"},
+		{name: "nested file", path: "servers/a.yaml", want: "name: a\n"},
+		{name: "dot segments inside root", path: "servers/../index.yaml", want: "servers: []\n"},
+		{name: "symlink inside root", path: "alias.yaml", want: "name: a\n"},
+		{name: "parent directory", path: "../secret.txt", wantErr: true},
+		{name: "absolute path", path: filepath.Join(parent, "secret.txt"), wantErr: true},
+		{name: "symlink out of root", path: "escape.yaml", wantErr: true},
+		{name: "symlinked directory out of root", path: "servers/up/secret.txt", wantErr: true},
+		{name: "missing file", path: "servers/b.yaml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ReadFile(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ReadFile(%q) = %q, want error", tt.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadFile(%q): %v", tt.path, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ReadFile(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "index.yaml", "servers: []\n")
+	writeFile(t, root, "servers/a.yaml", "name: a\n")
+	writeFile(t, root, "servers/nested/b.json", `{"name":"b"}`)
+	writeFile(t, root, "servers/notes.txt", "not a server\n")
+	writeFile(t, root, "servers-old/c.yaml", "name: c\n")
+	writeFile(t, root, "servers/.git/config", "[core]\n")
+
+	s := newStore(t, root)
+
+	var walked []string
+	match := func(p string) bool { return !strings.HasSuffix(p, ".txt") }
+	err := s.WalkFiles("servers", match, func(p string, content []byte) error {
+		if len(content) == 0 {
+			t.Errorf("%s read empty", p)
+		}
+		walked = append(walked, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkFiles: %v", err)
+	}
+
+	want := []string{"servers/a.yaml", "servers/nested/b.json"}
+	if !slices.Equal(walked, want) {
+		t.Errorf("WalkFiles visited %v, want %v", walked, want)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "index.yaml", "servers: []\n")
+	s := newStore(t, root)
+	ctx := context.Background()
+
+	initial := s.CurrentRevision()
+	if changed, err := s.Refresh(ctx); err != nil || changed {
+		t.Fatalf("Refresh of an unchanged directory = %v, %v; want false", changed, err)
+	}
+
+	writeFile(t, root, "servers/a.yaml", "name: a\n")
+	changed, err := s.Refresh(ctx)
+	if err != nil || !changed {
+		t.Fatalf("Refresh after adding a file = %v, %v; want true", changed, err)
+	}
+	added := s.CurrentRevision()
+	if added == initial {
+		t.Error("revision did not change when a file was added")
+	}
+
+	writeFile(t, root, "servers/a.yaml", "name: a\ndescription: longer\n")
+	if changed, err := s.Refresh(ctx); err != nil || !changed {
+		t.Fatalf("Refresh after editing a file = %v, %v; want true", changed, err)
+	}
+	if s.CurrentRevision() == added {
+		t.Error("revision did not change when a file was edited")
+	}
+
+	// Version control metadata is not part of the catalog
+	writeFile(t, root, ".git/HEAD", "ref: refs/heads/main\n")
+	if changed, err := s.Refresh(ctx); err != nil || changed {
+		t.Errorf("Refresh after a .git change = %v, %v; want false", changed, err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "index.yaml", "servers: []\n")
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "empty path", path: ""},
+		{name: "missing directory", path: filepath.Join(root, "missing")},
+		{name: "file", path: filepath.Join(root, "index.yaml")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(Config{Path: tt.path}); err == nil {
+				t.Errorf("New(%q) succeeded, want error", tt.path)
+			}
+		})
+	}
+}
@@ -76,6 +76,20 @@ var (
 		},
 	)
 
+	RegistryCacheCoalesced = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "registry_cache_coalesced_total",
+			Help: "Total number of cache misses that shared a load already in flight",
+		},
+	)
+
+	RegistryCacheNegativeHits = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "registry_cache_negative_hits_total",
+			Help: "Total number of lookups of unknown servers answered by the negative cache",
+		},
+	)
+
 	RegistryServersTotal = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "registry_servers_total",
@@ -96,6 +110,13 @@ var (
 			Help: "Whether the index is valid (1) or not (0)",
 		},
 	)
+
+	RegistryIndexDrift = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "registry_index_drift_entries",
+			Help: "Number of differences between index.yaml and the scanned server files",
+		},
+	)
 )
 
 // Metrics returns a middleware that records Prometheus metrics
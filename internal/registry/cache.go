@@ -0,0 +1,260 @@
+package registry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/middleware"
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// Cache modes
+const (
+	// CacheModePreload parses every server at sync time and serves
+	// pre-serialized responses without touching the LRU
+	CacheModePreload = "preload"
+	// CacheModeLazy keeps only the index in memory and parses servers on
+	// demand through a bounded LRU, for catalogs too large to preload
+	CacheModeLazy = "lazy"
+)
+
+// defaultPageSize is the list page size that responses are pre-serialized for
+const defaultPageSize = 30
+
+// encodedSnapshot holds the pre-serialized responses of a preloaded snapshot
+type encodedSnapshot struct {
+	servers map[string][]byte // GetServer responses by name
+	items   [][]byte          // list entries, aligned with Index.Servers
+	pages   [][]byte          // list responses of defaultPageSize servers
+	bytes   int64             // estimated memory held; see CacheStats
+}
+
+// cachedServer is a lazily loaded server, the size of its definition and
+// its digest, which tells whether it is current for a snapshot
+type cachedServer struct {
+	server *domain.ServerJSON
+	size   int64
+	digest [sha256.Size]byte
+}
+
+// encodeSnapshot serializes the response for every server and every
+// default-sized list page. Servers the sync did not change reuse the
+// encoding of the previous snapshot.
+func encodeSnapshot(snap, prev *Snapshot) (*encodedSnapshot, error) {
+	enc := &encodedSnapshot{
+		servers: make(map[string][]byte, len(snap.Index.Servers)),
+		items:   make([][]byte, len(snap.Index.Servers)),
+	}
+
+	var changed map[string]bool
+	if snap.diff != nil && prev.encoded != nil {
+		changed = affected(snap.diff)
+	}
+
+	for i, entry := range snap.Index.Servers {
+		if changed != nil && !changed[entry.Name] && sameChange(prev.changes[entry.Name], snap.changes[entry.Name]) {
+			item := prev.encoded.items[prev.positions[entry.Name]]
+			enc.servers[entry.Name] = prev.encoded.servers[entry.Name]
+			enc.items[i] = item
+			enc.bytes += int64(len(enc.servers[entry.Name]) + len(item))
+			continue
+		}
+
+		item, err := json.Marshal(snap.serverResponse(snap.Servers[entry.Name]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", entry.Name, err)
+		}
+
+		// A list entry is the single-server response without the newline
+		enc.servers[entry.Name] = append(item[:len(item):len(item)], '\n')
+		enc.items[i] = item
+		enc.bytes += int64(len(enc.servers[entry.Name]) + len(item))
+	}
+
+	listed := snap.listed()
+	for start := 0; start == 0 || start < listed.len(snap); start += defaultPageSize {
+		page, err := snap.page(enc.items, listed, start, defaultPageSize)
+		if err != nil {
+			return nil, err
+		}
+		enc.pages = append(enc.pages, page)
+		enc.bytes += int64(len(page))
+	}
+
+	return enc, nil
+}
+
+// serverResponse wraps a server with the _meta served for its latest
+// version, both on its own and in list responses
+func (s *Snapshot) serverResponse(server *domain.ServerJSON) domain.ServerResponse {
+	change := s.changes[server.Name]
+	official := &domain.OfficialMeta{
+		PublishedAt: change.created,
+		UpdatedAt:   change.updated,
+		Commit:      change.commit,
+		IsLatest:    true,
+	}
+	official.SetStatus(s.statuses[server.Name])
+
+	return domain.ServerResponse{
+		Server: *server,
+		Meta: &domain.ServerMeta{
+			Official:   official,
+			Validation: validationMeta(s.validation[server.Name]),
+			Labels:     s.labels(server.Name),
+		},
+	}
+}
+
+// labels returns the index.yaml labels of a server
+func (s *Snapshot) labels(name string) map[string]string {
+	entry, ok := s.entry(name)
+	if !ok || len(entry.Labels) == 0 {
+		return nil
+	}
+	return entry.Labels
+}
+
+// page assembles a list response from the encoded entries of a selection,
+// byte for byte what encoding the equivalent ServerListResponse would produce
+func (s *Snapshot) page(items [][]byte, sel selection, start, limit int) ([]byte, error) {
+	total := sel.len(s)
+	end := min(start+limit, total)
+
+	var nextCursor string
+	if end < total {
+		nextCursor = cursor{Revision: s.Revision, Position: end, Query: sel.query}.encode()
+	}
+	metadata, err := json.Marshal(domain.ListMetadata{
+		NextCursor: nextCursor,
+		Count:      end - start,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"servers":[`)
+	for i := start; i < end; i++ {
+		if i > start {
+			buf.WriteByte(',')
+		}
+		buf.Write(items[sel.at(i)])
+	}
+	buf.WriteString(`],"metadata":`)
+	buf.Write(metadata)
+	buf.WriteString("}\n")
+
+	return buf.Bytes(), nil
+}
+
+// server returns a parsed server. Preloaded snapshots hold every server;
+// in lazy mode servers come from the LRU, and a miss reads the definition
+// as of the snapshot's revision. Concurrent misses for one server share a
+// single load, and names found unknown are remembered until the next sync.
+func (r *Registry) server(snap *Snapshot, name string) (*domain.ServerJSON, error) {
+	if r.cacheMode == CacheModePreload {
+		server, ok := snap.Servers[name]
+		if !ok {
+			return nil, r.unknownServer(snap.Revision, name)
+		}
+		r.recordHit()
+		return server, nil
+	}
+
+	change, ok := snap.change(name)
+	if cached, hit := r.cache.Get(name); hit && ok && cached.digest == change.digest {
+		r.recordHit()
+		return cached.server, nil
+	}
+	if revision, _ := r.negative.Peek(name); revision == snap.Revision {
+		return nil, r.unknownServer(snap.Revision, name)
+	}
+	r.recordMiss()
+
+	key := snap.Revision + "\x00" + name
+	server, shared, err := r.loads.do(key, func() (*domain.ServerJSON, error) {
+		return r.loadServer(snap, name)
+	})
+	if shared {
+		r.cacheCoalesced.Add(1)
+		middleware.RegistryCacheCoalesced.Inc()
+	}
+	return server, err
+}
+
+// loadServer reads and parses a server for the lazy cache
+func (r *Registry) loadServer(snap *Snapshot, name string) (*domain.ServerJSON, error) {
+	entry, ok := snap.entry(name)
+	if !ok {
+		r.negative.Add(name, snap.Revision)
+		return nil, fmt.Errorf("server not found: %s", name)
+	}
+
+	content, err := r.readAt(snap.Revision, entry.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", entry.Path, err)
+	}
+
+	parsed, err := parseServer(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", entry.Path, err)
+	}
+
+	cached := &cachedServer{
+		server: parsed.server,
+		size:   int64(len(content)),
+		digest: snap.changes[name].digest,
+	}
+	// Replace an entry read at a revision where the server differed
+	r.cache.Remove(name)
+	if found, _ := r.cache.ContainsOrAdd(name, cached); !found {
+		r.cachedBytes.Add(cached.size)
+	}
+
+	return parsed.server, nil
+}
+
+// unknownServer records a lookup of a name a revision does not have. The
+// first lookup is a miss; repeats are served from the negative cache.
+func (r *Registry) unknownServer(revision, name string) error {
+	if cached, ok := r.negative.Get(name); ok && cached == revision {
+		r.negativeHits.Add(1)
+		middleware.RegistryCacheNegativeHits.Inc()
+	} else {
+		r.recordMiss()
+		r.negative.Add(name, revision)
+	}
+	return fmt.Errorf("server not found: %s", name)
+}
+
+func (r *Registry) recordHit() {
+	r.cacheHits.Add(1)
+	middleware.RegistryCacheHits.Inc()
+}
+
+func (r *Registry) recordMiss() {
+	r.cacheMisses.Add(1)
+	middleware.RegistryCacheMisses.Inc()
+}
+
+// readAt reads a file as of a revision when the source keeps history, and
+// from the current tree otherwise
+func (r *Registry) readAt(revision, path string) ([]byte, error) {
+	if rr, ok := r.store.(source.RevisionReader); ok {
+		return rr.ReadFileAt(revision, path)
+	}
+	return r.store.ReadFile(path)
+}
+
+// encodeJSON encodes v the way the API writes JSON responses
+func encodeJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,51 @@
+package registry
+
+import "testing"
+
+func TestCacheModes(t *testing.T) {
+	files := catalogFiles(40)
+
+	t.Run("preload", func(t *testing.T) {
+		r := newTestRegistry(t, Config{Store: localStore(t, files), CacheMode: CacheModePreload})
+
+		// Every server is parsed and serialized before the first request
+		stats := r.CacheStats()
+		if stats.Mode != CacheModePreload || stats.Size != 40 || stats.Capacity != 40 {
+			t.Errorf("stats = %+v, want all 40 servers preloaded", stats)
+		}
+		if stats.EstimatedMemoryBytes <= 0 {
+			t.Errorf("estimated memory = %d, want the serialized size", stats.EstimatedMemoryBytes)
+		}
+		if _, err := r.GetServer("com.example/server-007"); err != nil {
+			t.Fatal(err)
+		}
+		if stats := r.CacheStats(); stats.HitRate != 1 {
+			t.Errorf("hit rate = %v, want every lookup served from memory", stats.HitRate)
+		}
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		r := newTestRegistry(t, Config{Store: localStore(t, files), CacheMode: CacheModeLazy, CacheSize: 10})
+
+		if stats := r.CacheStats(); stats.Size != 0 || stats.Capacity != 10 || stats.EstimatedMemoryBytes != 0 {
+			t.Errorf("stats before any lookup = %+v, want an empty cache", stats)
+		}
+		for range 2 {
+			if _, err := r.GetServer("com.example/server-007"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		stats := r.CacheStats()
+		if stats.Size != 1 || stats.HitRate != 0.5 || stats.EstimatedMemoryBytes <= 0 {
+			t.Errorf("stats = %+v, want one cached server, read once", stats)
+		}
+
+		// A full listing cannot grow the cache beyond its capacity
+		if _, err := r.ListServers(ListOptions{Limit: 40}); err != nil {
+			t.Fatal(err)
+		}
+		if stats := r.CacheStats(); stats.Size != 10 {
+			t.Errorf("cache holds %d servers, want its capacity of 10", stats.Size)
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// ErrNoHistory is returned by Changes for sources without commit history
+var ErrNoHistory = errors.New("source has no commit history")
+
+// historySource is a source that can compare the catalog at two commits
+type historySource interface {
+	source.Timeline
+	source.Differ
+	source.RevisionReader
+}
+
+// Changes lists the servers added, removed or modified between a commit,
+// or the catalog as of a time, and the served revision. Pass either a
+// commit hash or a time. Modified servers carry field-level differences of
+// their definition and lifecycle state.
+func (r *Registry) Changes(commit string, since time.Time) (*domain.ChangesResponse, error) {
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil, errors.New("index not loaded")
+	}
+	hs, ok := r.store.(historySource)
+	if !ok {
+		return nil, ErrNoHistory
+	}
+
+	var base string
+	var err error
+	if commit != "" {
+		base, err = hs.ResolveCommit(snap.Revision, commit)
+	} else {
+		base, err = hs.CommitAt(snap.Revision, since)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &domain.ChangesResponse{
+		From:    base,
+		To:      snap.Revision,
+		Changes: []domain.ServerChange{},
+	}
+	if base == snap.Revision {
+		return resp, nil
+	}
+
+	changed := make(map[string]bool)
+	baseEntries := make(map[string]domain.IndexEntry)
+	if base != "" {
+		paths, err := hs.ChangedPaths(base, snap.Revision)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			changed[p] = true
+		}
+		baseEntries, err = r.entriesAt(hs, base, snap, paths)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(snap.Index.Servers)+len(baseEntries))
+	for _, entry := range snap.Index.Servers {
+		names = append(names, entry.Name)
+	}
+	for name := range baseEntries {
+		if _, ok := snap.positions[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		entry, inHead := snap.entry(name)
+		baseEntry, inBase := baseEntries[name]
+
+		var change *domain.ServerChange
+		switch {
+		case !inBase:
+			change, err = r.addedServer(snap, name)
+		case !inHead:
+			change = r.removedServer(hs, base, baseEntry)
+		default:
+			change, err = r.modifiedServer(hs, snap, base, *entry, baseEntry, changed)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			resp.Changes = append(resp.Changes, *change)
+		}
+	}
+
+	return resp, nil
+}
+
+// entriesAt returns the index entries of an earlier revision by name. In
+// scan modes a file the diff does not list is unchanged, so only changed
+// files are read; otherwise index.yaml is read as of the revision.
+func (r *Registry) entriesAt(hs historySource, revision string, snap *Snapshot, changed []string) (map[string]domain.IndexEntry, error) {
+	entries := make(map[string]domain.IndexEntry)
+
+	if r.indexMode == IndexModeFile {
+		// A revision without a usable index served nothing
+		content, err := hs.ReadFileAt(revision, indexFile)
+		if err != nil {
+			return entries, nil
+		}
+		var index domain.Index
+		if err := yaml.Unmarshal(content, &index); err != nil {
+			return entries, nil
+		}
+		for _, entry := range index.Servers {
+			entries[entry.Name] = entry
+		}
+		return entries, nil
+	}
+
+	isChanged := make(map[string]bool, len(changed))
+	for _, p := range changed {
+		isChanged[p] = true
+	}
+	for _, entry := range snap.Index.Servers {
+		if p, _ := source.CleanPath(entry.Path); !isChanged[p] {
+			entries[entry.Name] = domain.IndexEntry{Name: entry.Name, Path: entry.Path}
+		}
+	}
+
+	for _, p := range changed {
+		if !source.InDir(p, r.scanDir) || !isServerFile(p) {
+			continue
+		}
+		server, _, err := readServerAt(hs, revision, p)
+		if err != nil || server.Name == "" {
+			continue // added since, or never served
+		}
+		entries[server.Name] = domain.IndexEntry{Name: server.Name, Path: p}
+	}
+	return entries, nil
+}
+
+func (r *Registry) addedServer(snap *Snapshot, name string) (*domain.ServerChange, error) {
+	server, err := r.server(snap, name)
+	if err != nil {
+		return nil, err
+	}
+	c := snap.changes[name]
+	return &domain.ServerChange{
+		Name:      name,
+		Type:      domain.ChangeAdded,
+		Version:   server.Version,
+		Commit:    c.commit,
+		UpdatedAt: c.updated,
+	}, nil
+}
+
+// removedServer reports a server that is no longer served, or returns nil
+// if it was not served at base either. As in sync diffs, servers that are
+// quarantined or excluded now count as removed.
+func (r *Registry) removedServer(hs historySource, base string, entry domain.IndexEntry) *domain.ServerChange {
+	loaded, err := r.loadEntry(entry, func(path string) ([]byte, error) {
+		return hs.ReadFileAt(base, path)
+	})
+	if err != nil {
+		return nil
+	}
+	issues := mergeIssues(loaded.issues, domain.ValidationIssues(loaded.server))
+	if len(issues) > 0 && r.validationPolicy == ValidationStrict {
+		return nil
+	}
+	return &domain.ServerChange{Name: entry.Name, Type: domain.ChangeRemoved, Version: loaded.server.Version}
+}
+
+// modifiedServer compares a server at base with the snapshot, or returns
+// nil if neither its definition nor its lifecycle state changed
+func (r *Registry) modifiedServer(hs historySource, snap *Snapshot, base string, entry, baseEntry domain.IndexEntry, changed map[string]bool) (*domain.ServerChange, error) {
+	path, _ := source.CleanPath(entry.Path)
+	basePath, err := source.CleanPath(baseEntry.Path)
+	if err != nil {
+		return nil, nil
+	}
+	if path == basePath && !changed[path] && reflect.DeepEqual(entry.Status, baseEntry.Status) {
+		return nil, nil
+	}
+
+	c := snap.changes[entry.Name]
+	change := &domain.ServerChange{
+		Name:      entry.Name,
+		Type:      domain.ChangeModified,
+		Commit:    c.commit,
+		UpdatedAt: c.updated,
+	}
+
+	current, err := r.server(snap, entry.Name)
+	if err != nil {
+		return nil, err
+	}
+	change.Version = current.Version
+
+	old, content, err := readServerAt(hs, base, basePath)
+	if err != nil {
+		// Unparseable at base, so there are no fields to compare
+		return change, nil
+	}
+
+	fields, err := domain.DiffServers(old, current)
+	if err != nil {
+		return nil, err
+	}
+
+	oldState, newState := domain.StatusActive, domain.StatusActive
+	if st, _, err := resolveStatus(baseEntry, content); err == nil && st != nil {
+		oldState = st.State
+	}
+	if st := snap.statuses[entry.Name]; st != nil {
+		newState = st.State
+	}
+	if oldState != newState {
+		fields = append(fields, domain.FieldChange{
+			Path:        "status",
+			Type:        domain.ChangeModified,
+			Old:         oldState,
+			New:         newState,
+			Description: fmt.Sprintf("status changed from %s to %s", oldState, newState),
+		})
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	change.Fields = fields
+	return change, nil
+}
+
+// readServerAt reads and parses a server definition as of a revision, and
+// returns it converted to the current schema revision
+func readServerAt(hs historySource, revision, path string) (*domain.ServerJSON, []byte, error) {
+	content, err := hs.ReadFileAt(revision, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	parsed, err := parseServer(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s at %s: %w", path, revision, err)
+	}
+	return parsed.server, parsed.content, nil
+}
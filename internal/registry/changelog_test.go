@@ -0,0 +1,112 @@
+package registry
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/source"
+)
+
+func TestChanges(t *testing.T) {
+	repo := newGitFixture(t)
+	hour := func(n int) time.Time { return fixtureStart.Add(time.Duration(n) * time.Hour) }
+	remote := func(url string) string {
+		return "remotes:\n  - type: streamable-http\n    url: " + url + "\n"
+	}
+
+	first := repo.commit(map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/old
+    path: servers/old.yaml
+  - name: com.example/weather
+    path: servers/weather.yaml
+`,
+		"servers/old.yaml":     serverYAML("com.example/old", "1.0.0", "Old"),
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.0.0", "Weather") + remote("https://v1.example.com/mcp"),
+	})
+	changed := repo.commit(map[string]string{
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.1.0", "Weather") + remote("https://v2.example.com/mcp"),
+	})
+	replaced := repo.commit(map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/new
+    path: servers/new.yaml
+  - name: com.example/weather
+    path: servers/weather.yaml
+`,
+		"servers/old.yaml": "",
+		"servers/new.yaml": serverYAML("com.example/new", "1.0.0", "New"),
+	})
+
+	r := newTestRegistry(t, Config{Store: repo.store()})
+
+	t.Run("since commit", func(t *testing.T) {
+		changes, err := r.Changes(first[:12], time.Time{})
+		if err != nil {
+			t.Fatalf("Changes: %v", err)
+		}
+		if changes.From != first || changes.To != replaced {
+			t.Errorf("changes span %s..%s, want %s..%s", changes.From, changes.To, first, replaced)
+		}
+
+		byName := make(map[string]domain.ServerChange)
+		for _, c := range changes.Changes {
+			byName[c.Name] = c
+		}
+		if len(byName) != 3 || byName["com.example/new"].Type != domain.ChangeAdded ||
+			byName["com.example/old"].Type != domain.ChangeRemoved {
+			t.Fatalf("changes = %+v, want new added, old removed and weather modified", changes.Changes)
+		}
+
+		weather := byName["com.example/weather"]
+		if weather.Type != domain.ChangeModified || weather.Commit != changed || !weather.UpdatedAt.Equal(hour(1)) {
+			t.Errorf("weather change = %+v, want modified at %s", weather, changed)
+		}
+		var described []string
+		for _, f := range weather.Fields {
+			described = append(described, f.Description)
+		}
+		want := []string{"remote URL changed", "version changed from 1.0.0 to 1.1.0"}
+		if !slices.Equal(described, want) {
+			t.Errorf("weather fields = %q, want %q", described, want)
+		}
+	})
+
+	t.Run("since time", func(t *testing.T) {
+		// The catalog as of half past one is the second commit's
+		changes, err := r.Changes("", hour(1).Add(30*time.Minute))
+		if err != nil {
+			t.Fatalf("Changes: %v", err)
+		}
+		var names []string
+		for _, c := range changes.Changes {
+			names = append(names, c.Name+" "+c.Type)
+		}
+		if changes.From != changed || !slices.Equal(names, []string{"com.example/new added", "com.example/old removed"}) {
+			t.Errorf("changes from %s = %v, want only the last commit's", changes.From, names)
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		changes, err := r.Changes(replaced, time.Time{})
+		if err != nil || len(changes.Changes) != 0 {
+			t.Errorf("Changes(served commit) = %+v, %v; want none", changes, err)
+		}
+	})
+
+	t.Run("unknown commit", func(t *testing.T) {
+		if _, err := r.Changes("0123456789abcdef", time.Time{}); !errors.Is(err, source.ErrUnknownCommit) {
+			t.Errorf("Changes(unknown) error = %v, want ErrUnknownCommit", err)
+		}
+	})
+
+	t.Run("no history", func(t *testing.T) {
+		local := newTestRegistry(t, Config{Store: localStore(t, validationFiles)})
+		if _, err := local.Changes("", hour(0)); !errors.Is(err, ErrNoHistory) {
+			t.Errorf("Changes on a local directory error = %v, want ErrNoHistory", err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// serverChange records when a server was created and last changed, and the
+// digest of its definition file
+type serverChange struct {
+	digest  [sha256.Size]byte
+	created time.Time
+	updated time.Time
+	commit  string // last commit that changed the definition
+}
+
+// change returns the change record of a server
+func (s *Snapshot) change(name string) (serverChange, bool) {
+	if s == nil {
+		return serverChange{}, false
+	}
+	c, ok := s.changes[name]
+	return c, ok
+}
+
+// sameChange reports whether two change records date a server the same way
+func sameChange(a, b serverChange) bool {
+	return a.digest == b.digest && a.created.Equal(b.created) && a.updated.Equal(b.updated) && a.commit == b.commit
+}
+
+// trackChanges dates the creation and last change of every server. Sources
+// that track changes date them from history. Otherwise, or if the history
+// cannot be read, a server counts as changed at the first snapshot whose
+// definition differs from the previous one.
+func (r *Registry) trackChanges(revision string, entries []domain.IndexEntry, digests map[string][sha256.Size]byte, now time.Time) map[string]serverChange {
+	var files map[string]source.FileChange
+	if ct, ok := r.store.(source.ChangeTracker); ok {
+		var err error
+		files, err = ct.FileChanges(revision)
+		if err != nil {
+			r.logger.Warn("failed to read change history, dating changes from syncs",
+				"revision", revision,
+				"error", err,
+			)
+		}
+	}
+
+	prev := r.snapshot.Load()
+	changes := make(map[string]serverChange, len(entries))
+	for _, entry := range entries {
+		c := serverChange{digest: digests[entry.Name]}
+
+		// Accepted entries already passed CleanPath
+		path, _ := source.CleanPath(entry.Path)
+		if fc, ok := files[path]; ok {
+			c.created, c.updated, c.commit = fc.Created, fc.Updated, fc.Commit
+		} else if old, ok := prev.change(entry.Name); ok {
+			c.created, c.updated, c.commit = old.created, old.updated, old.commit
+			if old.digest != c.digest {
+				c.updated, c.commit = now, revision
+			}
+		} else {
+			c.created, c.updated, c.commit = now, now, revision
+		}
+
+		changes[entry.Name] = c
+	}
+
+	return changes
+}
@@ -0,0 +1,151 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Cursor errors
+var (
+	// ErrInvalidCursor is returned for cursors that cannot be decoded or
+	// were issued for a different query
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrCursorExpired is returned when the snapshot a cursor was issued
+	// for is no longer retained
+	ErrCursorExpired = errors.New("cursor expired")
+)
+
+// cursor is the decoded form of a pagination cursor. It pins the snapshot
+// a listing started on, so later pages read the same data even if a sync
+// happens in between.
+type cursor struct {
+	Revision string `json:"r"`
+	Position int    `json:"p"` // offset of the next page in the selection
+	Query    string `json:"q"` // fingerprint of the query
+}
+
+func (c cursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("%w: not a cursor issued by this registry", ErrInvalidCursor)
+	}
+	if err := json.Unmarshal(b, &c); err != nil || c.Revision == "" || c.Position < 0 {
+		return c, fmt.Errorf("%w: not a cursor issued by this registry", ErrInvalidCursor)
+	}
+	return c, nil
+}
+
+// listedQuery is the fingerprint of the default listing
+var listedQuery = ListOptions{}.fingerprint()
+
+// fingerprint identifies the servers a query selects, ignoring the cursor
+// and page size, so a cursor cannot be replayed against another query
+func (o ListOptions) fingerprint() string {
+	var sb strings.Builder
+	field := func(name, value string) {
+		sb.WriteString(name)
+		sb.WriteByte('=')
+		sb.WriteString(strconv.Quote(value))
+		sb.WriteByte('&')
+	}
+	boolPtr := func(b *bool) string {
+		if b == nil {
+			return ""
+		}
+		return strconv.FormatBool(*b)
+	}
+
+	field("search", o.Search)
+	field("registry_type", strings.Join(o.Filter.RegistryType, ","))
+	field("transport", strings.Join(o.Filter.Transport, ","))
+	field("runtime_hint", strings.Join(o.Filter.RuntimeHint, ","))
+	field("remote_type", strings.Join(o.Filter.RemoteType, ","))
+	field("has_remote", boolPtr(o.Filter.HasRemote))
+	field("has_package", boolPtr(o.Filter.HasPackage))
+	field("labels", fmt.Sprint(o.Labels))
+	if !o.UpdatedSince.IsZero() {
+		field("updated_since", o.UpdatedSince.UTC().Format("2006-01-02T15:04:05.999999999Z"))
+	}
+	field("include_deleted", strconv.FormatBool(o.IncludeDeleted))
+
+	sum := sha256.Sum256([]byte(sb.String()))
+	return hex.EncodeToString(sum[:6])
+}
+
+// resolvePage finds the snapshot, selection and offset a list request
+// reads. Without a cursor that is the start of the current snapshot;
+// with one it is wherever the cursor left off in its own snapshot.
+func (r *Registry) resolvePage(opts ListOptions) (*Snapshot, selection, int, error) {
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil, selection{}, 0, errors.New("index not loaded")
+	}
+
+	if opts.Cursor == "" {
+		return snap, snap.selection(opts), 0, nil
+	}
+
+	c, err := decodeCursor(opts.Cursor)
+	if err != nil {
+		return nil, selection{}, 0, err
+	}
+	if c.Query != opts.fingerprint() {
+		return nil, selection{}, 0, fmt.Errorf("%w: cursor was issued for a different query", ErrInvalidCursor)
+	}
+
+	snap = r.retainedSnapshot(c.Revision)
+	if snap == nil {
+		return nil, selection{}, 0, fmt.Errorf("%w: revision %s is no longer served, restart from the first page",
+			ErrCursorExpired, c.Revision)
+	}
+
+	sel := snap.selection(opts)
+	if c.Position > sel.len(snap) {
+		return nil, selection{}, 0, fmt.Errorf("%w: position out of range", ErrInvalidCursor)
+	}
+	return snap, sel, c.Position, nil
+}
+
+// retainSnapshot keeps a newly served snapshot for cursors, dropping the
+// oldest beyond the retention limit. Callers must hold loadMu.
+func (r *Registry) retainSnapshot(snap *Snapshot) {
+	r.retainedMu.Lock()
+	defer r.retainedMu.Unlock()
+
+	retained := make([]*Snapshot, 0, r.retention)
+	for _, s := range r.retained {
+		if s.Revision != snap.Revision {
+			retained = append(retained, s)
+		}
+	}
+	retained = append(retained, snap)
+	if len(retained) > r.retention {
+		retained = retained[len(retained)-r.retention:]
+	}
+	r.retained = retained
+}
+
+// retainedSnapshot returns the newest retained snapshot of a revision
+func (r *Registry) retainedSnapshot(revision string) *Snapshot {
+	r.retainedMu.Lock()
+	defer r.retainedMu.Unlock()
+
+	for i := len(r.retained) - 1; i >= 0; i-- {
+		if r.retained[i].Revision == revision {
+			return r.retained[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name    string
+		input   string
+		want    cursor
+		wantErr bool
+	}{
+		{
+			name:  "round trip",
+			input: cursor{Revision: "abc123", Position: 30, Query: listedQuery}.encode(),
+			want:  cursor{Revision: "abc123", Position: 30, Query: listedQuery},
+		},
+		{
+			name:  "first position",
+			input: raw(`{"r":"abc123","p":0,"q":"x"}`),
+			want:  cursor{Revision: "abc123", Position: 0, Query: "x"},
+		},
+		{name: "empty", input: "", wantErr: true},
+		{name: "not base64", input: "!!!", wantErr: true},
+		{name: "not json", input: raw("abc"), wantErr: true},
+		{name: "missing revision", input: raw(`{"p":1,"q":"x"}`), wantErr: true},
+		{name: "negative position", input: raw(`{"r":"abc","p":-1,"q":"x"}`), wantErr: true},
+		{name: "wrong type", input: raw(`{"r":"abc","p":"1"}`), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("decodeCursor(%q) error = %v, want ErrInvalidCursor", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeCursor(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("decodeCursor(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	yes := true
+
+	tests := []struct {
+		name string
+		a, b ListOptions
+		same bool
+	}{
+		{
+			name: "cursor and limit are ignored",
+			a:    ListOptions{Search: "git"},
+			b:    ListOptions{Search: "git", Cursor: "abc", Limit: 5},
+			same: true,
+		},
+		{
+			name: "search differs",
+			a:    ListOptions{Search: "git"},
+			b:    ListOptions{Search: "gitlab"},
+		},
+		{
+			name: "filter differs",
+			a:    ListOptions{},
+			b:    ListOptions{Filter: Filter{HasRemote: &yes}},
+		},
+		{
+			name: "include deleted differs",
+			a:    ListOptions{},
+			b:    ListOptions{IncludeDeleted: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := tt.a.fingerprint() == tt.b.fingerprint(); same != tt.same {
+				t.Errorf("fingerprints equal = %v, want %v", same, tt.same)
+			}
+		})
+	}
+}
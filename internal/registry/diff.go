@@ -0,0 +1,169 @@
+package registry
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// maxSyncDiffs is how many sync diffs are kept for diagnostics
+const maxSyncDiffs = 20
+
+// diffSnapshots compares a snapshot with the one it replaces. Changed paths
+// come from the source when it can diff revisions, and otherwise from the
+// digests of the definitions. A server is modified when its file changed
+// or its index entry did.
+func (r *Registry) diffSnapshots(prev, snap *Snapshot) *domain.SyncDiff {
+	diff := &domain.SyncDiff{
+		From:     prev.Revision,
+		To:       snap.Revision,
+		SyncedAt: snap.LoadedAt,
+		Added:    []string{},
+		Removed:  []string{},
+		Modified: []string{},
+	}
+
+	var changed map[string]bool
+	if d, ok := r.store.(source.Differ); ok && prev.Revision != snap.Revision {
+		paths, err := d.ChangedPaths(prev.Revision, snap.Revision)
+		if err != nil {
+			r.logger.Warn("failed to diff revisions, comparing definitions instead",
+				"from", prev.Revision,
+				"to", snap.Revision,
+				"error", err,
+			)
+		} else {
+			diff.Paths = paths
+			changed = make(map[string]bool, len(paths))
+			for _, p := range paths {
+				changed[p] = true
+			}
+		}
+	}
+
+	// Entries whose path is not clean were quarantined, so the index rows
+	// compared here always have one
+	var touched []string
+	for _, entry := range snap.Index.Servers {
+		path, _ := source.CleanPath(entry.Path)
+		old, ok := prev.entry(entry.Name)
+		if !ok {
+			diff.Added = append(diff.Added, entry.Name)
+			touched = append(touched, path)
+			continue
+		}
+
+		oldPath, _ := source.CleanPath(old.Path)
+		if changed[path] || changed[oldPath] ||
+			prev.changes[entry.Name].digest != snap.changes[entry.Name].digest ||
+			!reflect.DeepEqual(*old, entry) {
+			diff.Modified = append(diff.Modified, entry.Name)
+			touched = append(touched, path, oldPath)
+		}
+	}
+	for _, entry := range prev.Index.Servers {
+		if _, ok := snap.positions[entry.Name]; !ok {
+			diff.Removed = append(diff.Removed, entry.Name)
+			path, _ := source.CleanPath(entry.Path)
+			touched = append(touched, path)
+		}
+	}
+
+	if changed == nil {
+		diff.Paths = dedupe(touched)
+	}
+	return diff
+}
+
+// affected returns the names a diff added, removed or modified
+func affected(diff *domain.SyncDiff) map[string]bool {
+	names := make(map[string]bool, len(diff.Added)+len(diff.Removed)+len(diff.Modified))
+	for _, list := range [][]string{diff.Added, diff.Removed, diff.Modified} {
+		for _, name := range list {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// invalidate drops cached data of the servers a sync changed and keeps the
+// rest warm. Unknown names stay unknown unless the sync added them.
+func (r *Registry) invalidate(diff *domain.SyncDiff) {
+	changed := affected(diff)
+	for name := range changed {
+		if r.cache.Remove(name) {
+			diff.Invalidated++
+		}
+	}
+
+	// Histories read at the previous revision carry over unless the server
+	// changed; any others were read before an earlier sync
+	for _, key := range r.history.Keys() {
+		versions, ok := r.history.Peek(key)
+		if !ok {
+			continue
+		}
+		r.history.Remove(key)
+		revision, name, _ := strings.Cut(key, "\x00")
+		if revision != diff.From || changed[name] {
+			diff.Invalidated++
+			continue
+		}
+		r.history.Add(historyKey(diff.To, name), versions)
+	}
+
+	added := make(map[string]bool, len(diff.Added))
+	for _, name := range diff.Added {
+		added[name] = true
+	}
+	for _, name := range r.negative.Keys() {
+		revision, ok := r.negative.Peek(name)
+		if !ok {
+			continue
+		}
+		if added[name] || revision != diff.From {
+			r.negative.Remove(name)
+			diff.Invalidated++
+			continue
+		}
+		r.negative.Add(name, diff.To)
+	}
+}
+
+// recordSync keeps a sync diff for diagnostics, dropping the oldest
+func (r *Registry) recordSync(diff *domain.SyncDiff) {
+	r.syncsMu.Lock()
+	defer r.syncsMu.Unlock()
+
+	r.syncs = append(r.syncs, *diff)
+	if len(r.syncs) > maxSyncDiffs {
+		r.syncs = r.syncs[len(r.syncs)-maxSyncDiffs:]
+	}
+}
+
+// SyncDiffs returns what recent syncs changed, newest first
+func (r *Registry) SyncDiffs() []domain.SyncDiff {
+	r.syncsMu.Lock()
+	defer r.syncsMu.Unlock()
+
+	diffs := make([]domain.SyncDiff, len(r.syncs))
+	for i, d := range r.syncs {
+		diffs[len(r.syncs)-1-i] = d
+	}
+	return diffs
+}
+
+// dedupe sorts strings and drops duplicates and empty ones
+func dedupe(values []string) []string {
+	sort.Strings(values)
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" && (len(out) == 0 || out[len(out)-1] != v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
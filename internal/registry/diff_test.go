@@ -0,0 +1,92 @@
+package registry
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+func TestIncrementalInvalidation(t *testing.T) {
+	repo := newGitFixture(t)
+	index := func(names ...string) string {
+		s := "servers:\n"
+		for _, n := range names {
+			s += "  - name: com.example/" + n + "\n    path: servers/" + n + ".yaml\n"
+		}
+		return s
+	}
+	repo.commit(map[string]string{
+		"index.yaml":     index("a", "b", "c"),
+		"servers/a.yaml": serverYAML("com.example/a", "1.0.0", "A"),
+		"servers/b.yaml": serverYAML("com.example/b", "1.0.0", "B"),
+		"servers/c.yaml": serverYAML("com.example/c", "1.0.0", "C"),
+	})
+	store := repo.store()
+	r := newTestRegistry(t, Config{Store: store, CacheMode: CacheModeLazy})
+
+	get := func(name string) *domain.ServerJSON {
+		t.Helper()
+		server, err := r.GetServer("com.example/" + name)
+		if err != nil {
+			t.Fatalf("GetServer(%s): %v", name, err)
+		}
+		return server
+	}
+	sync := func(files map[string]string) *domain.SyncDiff {
+		t.Helper()
+		repo.commit(files)
+		if _, err := store.Pull(context.Background()); err != nil {
+			t.Fatalf("pull: %v", err)
+		}
+		diff, err := r.Refresh()
+		if err != nil {
+			t.Fatalf("refresh: %v", err)
+		}
+		return diff
+	}
+
+	// Three misses warm the cache, then three hits
+	for _, name := range []string{"a", "b", "c", "a", "b", "c"} {
+		get(name)
+	}
+
+	diff := sync(map[string]string{"servers/b.yaml": serverYAML("com.example/b", "1.1.0", "B")})
+	if !slices.Equal(diff.Paths, []string{"servers/b.yaml"}) || !slices.Equal(diff.Modified, []string{"com.example/b"}) ||
+		len(diff.Added) != 0 || len(diff.Removed) != 0 || diff.Invalidated != 1 {
+		t.Errorf("diff = %+v, want only com.example/b modified and invalidated", diff)
+	}
+	if stats := r.CacheStats(); stats.Size != 2 {
+		t.Errorf("cache holds %d servers after the sync, want the 2 unchanged ones", stats.Size)
+	}
+
+	// Unchanged servers stay cached and the hit rate carries over
+	get("a")
+	get("c")
+	if b := get("b"); b.Version != "1.1.0" {
+		t.Errorf("served b at %s, want the synced 1.1.0", b.Version)
+	}
+	if stats := r.CacheStats(); stats.HitRate != 5.0/9 {
+		t.Errorf("hit rate = %v, want 5 hits in 9 lookups", stats.HitRate)
+	}
+
+	diff = sync(map[string]string{
+		"index.yaml":     index("a", "b", "d"),
+		"servers/c.yaml": "",
+		"servers/d.yaml": serverYAML("com.example/d", "1.0.0", "D"),
+	})
+	if !slices.Equal(diff.Added, []string{"com.example/d"}) || !slices.Equal(diff.Removed, []string{"com.example/c"}) ||
+		len(diff.Modified) != 0 {
+		t.Errorf("diff = %+v, want d added and c removed", diff)
+	}
+	if want := []string{"index.yaml", "servers/c.yaml", "servers/d.yaml"}; !slices.Equal(diff.Paths, want) {
+		t.Errorf("changed paths = %v, want %v", diff.Paths, want)
+	}
+
+	// Each sync is kept for diagnostics, newest first
+	syncs := r.SyncDiffs()
+	if len(syncs) != 2 || syncs[0].To != r.Revision() || syncs[1].To != syncs[0].From {
+		t.Errorf("sync diffs = %+v, want both syncs newest first", syncs)
+	}
+}
@@ -0,0 +1,143 @@
+package registry
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// Facet names, matching the list query parameters
+const (
+	FacetRegistryType = "registry_type"
+	FacetTransport    = "transport"
+	FacetRuntimeHint  = "runtime_hint"
+	FacetRemoteType   = "remote_type"
+	FacetHasRemote    = "has_remote"
+	FacetHasPackage   = "has_package"
+)
+
+// Filter narrows a server list by package and remote attributes. Values
+// of one field are alternatives and every set field must match. The
+// package fields must all be matched by the same package, so "stdio via
+// uvx" does not match a server whose stdio package runs with npx.
+type Filter struct {
+	RegistryType []string
+	Transport    []string
+	RuntimeHint  []string
+	RemoteType   []string
+	HasRemote    *bool
+	HasPackage   *bool
+}
+
+// empty reports whether the filter matches every server
+func (f Filter) empty() bool {
+	return len(f.RegistryType) == 0 && len(f.Transport) == 0 && len(f.RuntimeHint) == 0 &&
+		len(f.RemoteType) == 0 && f.HasRemote == nil && f.HasPackage == nil
+}
+
+// serverFacets are the filterable attributes of a server, extracted at
+// sync time so lazy snapshots can filter without loading servers
+type serverFacets struct {
+	packages    []packageFacets
+	remoteTypes []string
+}
+
+type packageFacets struct {
+	registryType string
+	transport    string
+	runtimeHint  string
+}
+
+func extractFacets(server *domain.ServerJSON) serverFacets {
+	f := serverFacets{
+		packages:    make([]packageFacets, 0, len(server.Packages)),
+		remoteTypes: make([]string, 0, len(server.Remotes)),
+	}
+	for _, pkg := range server.Packages {
+		f.packages = append(f.packages, packageFacets{
+			registryType: pkg.RegistryType,
+			transport:    pkg.Transport.Type,
+			runtimeHint:  pkg.RuntimeHint,
+		})
+	}
+	for _, remote := range server.Remotes {
+		f.remoteTypes = append(f.remoteTypes, remote.Type)
+	}
+	return f
+}
+
+// matches reports whether a server satisfies the filter
+func (f Filter) matches(s serverFacets) bool {
+	if f.HasPackage != nil && *f.HasPackage != (len(s.packages) > 0) {
+		return false
+	}
+	if f.HasRemote != nil && *f.HasRemote != (len(s.remoteTypes) > 0) {
+		return false
+	}
+	if len(f.RemoteType) > 0 && !slices.ContainsFunc(s.remoteTypes, func(t string) bool {
+		return slices.Contains(f.RemoteType, t)
+	}) {
+		return false
+	}
+
+	if len(f.RegistryType) == 0 && len(f.Transport) == 0 && len(f.RuntimeHint) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(s.packages, func(p packageFacets) bool {
+		return oneOf(f.RegistryType, p.registryType) &&
+			oneOf(f.Transport, p.transport) &&
+			oneOf(f.RuntimeHint, p.runtimeHint)
+	})
+}
+
+// oneOf reports whether v is one of the wanted values, or nothing is wanted
+func oneOf(wanted []string, v string) bool {
+	return len(wanted) == 0 || slices.Contains(wanted, v)
+}
+
+// countFacets counts servers per facet value. A server counts once per
+// value however many of its packages or remotes share it.
+func countFacets(revision string, facets []serverFacets) *domain.FacetsResponse {
+	resp := &domain.FacetsResponse{
+		Revision: revision,
+		Total:    len(facets),
+		Facets: map[string]map[string]int{
+			FacetRegistryType: {},
+			FacetTransport:    {},
+			FacetRuntimeHint:  {},
+			FacetRemoteType:   {},
+			FacetHasRemote:    {},
+			FacetHasPackage:   {},
+		},
+	}
+
+	for _, s := range facets {
+		values := map[string]map[string]bool{
+			FacetRegistryType: {},
+			FacetTransport:    {},
+			FacetRuntimeHint:  {},
+			FacetRemoteType:   {},
+		}
+		for _, p := range s.packages {
+			values[FacetRegistryType][p.registryType] = true
+			values[FacetTransport][p.transport] = true
+			if p.runtimeHint != "" {
+				values[FacetRuntimeHint][p.runtimeHint] = true
+			}
+		}
+		for _, t := range s.remoteTypes {
+			values[FacetRemoteType][t] = true
+		}
+		for facet, vs := range values {
+			for v := range vs {
+				resp.Facets[facet][v]++
+			}
+		}
+
+		resp.Facets[FacetHasRemote][strconv.FormatBool(len(s.remoteTypes) > 0)]++
+		resp.Facets[FacetHasPackage][strconv.FormatBool(len(s.packages) > 0)]++
+	}
+
+	return resp
+}
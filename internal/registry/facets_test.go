@@ -0,0 +1,73 @@
+package registry
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{name: "none", want: []string{"com.example/maps", "com.example/notes", "com.example/tools", "com.example/weather"}},
+		{name: "stdio", filter: Filter{Transport: []string{"stdio"}}, want: []string{"com.example/notes", "com.example/tools", "com.example/weather"}},
+		{name: "stdio via uvx", filter: Filter{Transport: []string{"stdio"}, RuntimeHint: []string{"uvx"}}, want: []string{"com.example/notes"}},
+		// Both fields must be matched by one package: notes runs stdio
+		// with uvx and streamable-http with npx
+		{name: "stdio via npx", filter: Filter{Transport: []string{"stdio"}, RuntimeHint: []string{"npx"}}, want: []string{"com.example/weather"}},
+		{name: "either registry", filter: Filter{RegistryType: []string{"oci", "pypi"}}, want: []string{"com.example/notes", "com.example/tools"}},
+		{name: "remote type", filter: Filter{RemoteType: []string{"streamable-http"}}, want: []string{"com.example/maps"}},
+		{name: "has remote", filter: Filter{HasRemote: &yes}, want: []string{"com.example/maps", "com.example/notes"}},
+		{name: "remote only", filter: Filter{HasRemote: &yes, HasPackage: &no}, want: []string{"com.example/maps"}},
+		{name: "no match", filter: Filter{RegistryType: []string{"nuget"}}},
+	}
+
+	for _, mode := range []string{CacheModePreload, CacheModeLazy} {
+		r := newTestRegistry(t, Config{Store: localStore(t, catalogDefinitions), CacheMode: mode})
+
+		for _, tt := range tests {
+			t.Run(mode+"/"+tt.name, func(t *testing.T) {
+				list, err := r.ListServers(ListOptions{Filter: tt.filter})
+				if err != nil {
+					t.Fatalf("ListServers: %v", err)
+				}
+				var got []string
+				for _, s := range list.Servers {
+					got = append(got, s.Server.Name)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("listed %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestFacets(t *testing.T) {
+	r := newTestRegistry(t, Config{Store: localStore(t, catalogDefinitions)})
+
+	facets := r.Facets()
+	if facets == nil || facets.Total != 4 || facets.Revision != r.Revision() {
+		t.Fatalf("Facets() = %+v, want 4 servers at the served revision", facets)
+	}
+
+	// A server counts once per value, however many packages share it
+	want := map[string]map[string]int{
+		FacetRegistryType: {"npm": 2, "pypi": 1, "oci": 1},
+		FacetTransport:    {"stdio": 3, "streamable-http": 1},
+		FacetRuntimeHint:  {"npx": 2, "uvx": 1, "docker": 1},
+		FacetRemoteType:   {"streamable-http": 1, "sse": 1},
+		FacetHasRemote:    {"true": 2, "false": 2},
+		FacetHasPackage:   {"true": 3, "false": 1},
+	}
+	for facet, counts := range want {
+		if !maps.Equal(facets.Facets[facet], counts) {
+			t.Errorf("%s counts = %v, want %v", facet, facets.Facets[facet], counts)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/gitstore"
+	"github.com/mcpregistry/server/internal/localstore"
+)
+
+// fixtureStart is when the first commit of a fixture repository is made
+var fixtureStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// serverYAML renders a minimal valid server definition
+func serverYAML(name, version, description string) string {
+	return fmt.Sprintf("$schema: %s\nname: %s\ndescription: %s\nversion: %s\n",
+		domain.SchemaURL(domain.CurrentSchema), name, description, version)
+}
+
+// gitFixture is a repository on disk that tests commit catalog changes to,
+// one hour apart starting at fixtureStart
+type gitFixture struct {
+	t        *testing.T
+	dir      string
+	worktree *git.Worktree
+	when     time.Time
+}
+
+func newGitFixture(t *testing.T) *gitFixture {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	if err != nil {
+		t.Fatalf("init fixture repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("fixture worktree: %v", err)
+	}
+	return &gitFixture{t: t, dir: dir, worktree: worktree, when: fixtureStart}
+}
+
+// commit writes files, deleting those whose content is empty, and commits
+// them. It returns the commit hash.
+func (f *gitFixture) commit(files map[string]string) string {
+	f.t.Helper()
+	for name, content := range files {
+		if content == "" {
+			if _, err := f.worktree.Remove(name); err != nil {
+				f.t.Fatalf("remove %s: %v", name, err)
+			}
+			continue
+		}
+		writeFile(f.t, f.dir, name, content)
+		if _, err := f.worktree.Add(name); err != nil {
+			f.t.Fatalf("add %s: %v", name, err)
+		}
+	}
+
+	sig := &object.Signature{Name: "Fixture", Email: "fixture@example.com", When: f.when}
+	hash, err := f.worktree.Commit(fmt.Sprintf("commit at %s", f.when.Format(time.RFC3339)), &git.CommitOptions{
+		Author:            sig,
+		Committer:         sig,
+		AllowEmptyCommits: true,
+	})
+	if err != nil {
+		f.t.Fatalf("commit: %v", err)
+	}
+	f.when = f.when.Add(time.Hour)
+	return hash.String()
+}
+
+// symlink commits a symbolic link to target
+func (f *gitFixture) symlink(name, target string) string {
+	f.t.Helper()
+	path := filepath.Join(f.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		f.t.Fatal(err)
+	}
+	if err := os.Symlink(target, path); err != nil {
+		f.t.Fatal(err)
+	}
+	if _, err := f.worktree.Add(name); err != nil {
+		f.t.Fatalf("add %s: %v", name, err)
+	}
+	return f.commit(nil)
+}
+
+// store clones the fixture into memory
+func (f *gitFixture) store() *gitstore.Store {
+	f.t.Helper()
+	store, err := gitstore.New(gitstore.Config{
+		RepoURL:  f.dir,
+		InMemory: true,
+		Logger:   discardLogger(),
+	})
+	if err != nil {
+		f.t.Fatalf("create git store: %v", err)
+	}
+	if err := store.Clone(context.Background()); err != nil {
+		f.t.Fatalf("clone fixture: %v", err)
+	}
+	return store
+}
+
+// localStore writes files to a directory and serves it
+func localStore(t *testing.T, files map[string]string) *localstore.Store {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		writeFile(t, dir, name, content)
+	}
+	store, err := localstore.New(localstore.Config{Path: dir, Logger: discardLogger()})
+	if err != nil {
+		t.Fatalf("create local store: %v", err)
+	}
+	return store
+}
+
+// newTestRegistry creates a registry over store and loads its index
+func newTestRegistry(t *testing.T, cfg Config) *Registry {
+	t.Helper()
+	if cfg.Logger == nil {
+		cfg.Logger = discardLogger()
+	}
+	r, err := New(cfg)
+	if err != nil {
+		t.Fatalf("create registry: %v", err)
+	}
+	if err := r.LoadIndex(); err != nil {
+		t.Fatalf("load index: %v", err)
+	}
+	return r
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
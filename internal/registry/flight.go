@@ -0,0 +1,43 @@
+package registry
+
+import "sync"
+
+// flightGroup coalesces concurrent calls for the same key into one, so a
+// burst of misses for a popular server loads it once
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// do runs fn once per key at a time. Callers arriving while it runs wait
+// for and share its result, and shared reports whether this caller did.
+func (g *flightGroup[T]) do(key string, fn func() (T, error)) (val T, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[T])
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.val, true, c.err
+	}
+	c := &flightCall[T]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.val, c.err = fn()
+	return c.val, false, c.err
+}
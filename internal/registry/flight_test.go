@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcpregistry/server/internal/localstore"
+)
+
+// gatedStore holds reads of one file until the test opens the gate
+type gatedStore struct {
+	*localstore.Store
+	path  string
+	gate  chan struct{}
+	reads atomic.Int32
+}
+
+func (s *gatedStore) ReadFile(path string) ([]byte, error) {
+	if path == s.path {
+		s.reads.Add(1)
+		<-s.gate
+	}
+	return s.Store.ReadFile(path)
+}
+
+func TestConcurrentMissesCoalesce(t *testing.T) {
+	store := &gatedStore{
+		Store: localStore(t, catalogFiles(3)),
+		path:  "servers/server-001.yaml",
+		gate:  make(chan struct{}),
+	}
+	r, err := New(Config{Store: store, CacheMode: CacheModeLazy, Logger: discardLogger()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Building the snapshot reads every file once
+	close(store.gate)
+	if err := r.LoadIndex(); err != nil {
+		t.Fatal(err)
+	}
+	store.gate = make(chan struct{})
+	store.reads.Store(0)
+
+	const callers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := r.GetServer("com.example/server-001")
+			errs <- err
+		}()
+	}
+
+	// Let every caller miss and join the load before it completes
+	deadline := time.Now().Add(5 * time.Second)
+	for r.cacheMisses.Load() < callers {
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d of %d callers missed", r.cacheMisses.Load(), callers)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(store.gate)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetServer: %v", err)
+		}
+	}
+	if reads := store.reads.Load(); reads != 1 {
+		t.Errorf("definition read %d times, want once", reads)
+	}
+	if stats := r.CacheStats(); stats.Coalesced != callers-1 {
+		t.Errorf("coalesced = %d, want %d", stats.Coalesced, callers-1)
+	}
+}
+
+func TestNegativeCache(t *testing.T) {
+	for _, mode := range []string{CacheModePreload, CacheModeLazy} {
+		t.Run(mode, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, dir, "index.yaml", "servers:\n  - name: com.example/known\n    path: servers/known.yaml\n")
+			writeFile(t, dir, "servers/known.yaml", serverYAML("com.example/known", "1.0.0", "Known"))
+			store, err := localstore.New(localstore.Config{Path: dir, Logger: discardLogger()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := newTestRegistry(t, Config{Store: store, CacheMode: mode})
+
+			lookup := func(name string, wantNegativeHits int64) {
+				t.Helper()
+				r.GetServer(name)
+				if got := r.CacheStats().NegativeHits; got != wantNegativeHits {
+					t.Errorf("after looking up %s: negative hits = %d, want %d", name, got, wantNegativeHits)
+				}
+			}
+			lookup("com.example/new", 0)
+			lookup("com.example/new", 1)
+			lookup("com.example/new", 2)
+
+			// A sync forgets unknown names, so servers it adds are found
+			writeFile(t, dir, "index.yaml", "servers:\n  - name: com.example/known\n    path: servers/known.yaml\n"+
+				"  - name: com.example/new\n    path: servers/new.yaml\n")
+			writeFile(t, dir, "servers/new.yaml", serverYAML("com.example/new", "1.0.0", "New"))
+			if _, err := store.Refresh(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Refresh(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.GetServer("com.example/new"); err != nil {
+				t.Errorf("GetServer(new) after sync: %v", err)
+			}
+			lookup("com.example/new", 2)
+		})
+	}
+}
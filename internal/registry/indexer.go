@@ -0,0 +1,193 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// Index modes
+const (
+	// IndexModeFile serves index.yaml as committed
+	IndexModeFile = "file"
+	// IndexModeScan derives the index from server files and ignores index.yaml
+	IndexModeScan = "scan"
+	// IndexModeCompare derives the index from server files and reports
+	// drift against index.yaml
+	IndexModeCompare = "compare"
+)
+
+const indexFile = "index.yaml"
+
+// builtIndex is the outcome of resolving the index for a revision
+type builtIndex struct {
+	index       *domain.Index
+	drift       []string
+	quarantined []domain.QuarantinedEntry
+}
+
+// buildIndex produces the index for the current revision according to the
+// configured mode
+func (r *Registry) buildIndex(revision string) (*builtIndex, error) {
+	switch r.indexMode {
+	case IndexModeScan:
+		return r.scanIndex(revision)
+
+	case IndexModeCompare:
+		scanned, err := r.scanIndex(revision)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := r.readIndexFile()
+		if err != nil {
+			scanned.drift = []string{fmt.Sprintf("%s unusable: %v", indexFile, err)}
+		} else {
+			scanned.drift = compareIndexes(file, scanned.index)
+			mergeIndexMetadata(scanned.index, file)
+		}
+
+		for _, d := range scanned.drift {
+			r.logger.Warn("index drift", "revision", revision, "drift", d)
+		}
+		return scanned, nil
+
+	default:
+		index, err := r.readIndexFile()
+		if err != nil {
+			return nil, &SnapshotError{Revision: revision, Errors: []string{err.Error()}}
+		}
+		return &builtIndex{index: index}, nil
+	}
+}
+
+// readIndexFile reads and parses index.yaml
+func (r *Registry) readIndexFile() (*domain.Index, error) {
+	content, err := r.store.ReadFile(indexFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found: %v", indexFile, err)
+	}
+
+	var index domain.Index
+	if err := yaml.Unmarshal(content, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", indexFile, err)
+	}
+
+	return &index, nil
+}
+
+// scanIndex discovers server definitions under the scan directory and
+// derives an index entry from each. Files that cannot be parsed or do not
+// declare a name are quarantined.
+func (r *Registry) scanIndex(revision string) (*builtIndex, error) {
+	result := &builtIndex{
+		index: &domain.Index{
+			Commit:    revision,
+			UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	err := r.store.WalkFiles(r.scanDir, isServerFile, func(p string, content []byte) error {
+		var server domain.ServerJSON
+		if err := yaml.Unmarshal(content, &server); err != nil {
+			result.quarantined = append(result.quarantined, domain.QuarantinedEntry{
+				Path:   p,
+				Reason: fmt.Sprintf("failed to parse %s: %v", p, err),
+			})
+			return nil
+		}
+		if server.Name == "" {
+			result.quarantined = append(result.quarantined, domain.QuarantinedEntry{
+				Path:   p,
+				Reason: fmt.Sprintf("%s does not declare a name", p),
+			})
+			return nil
+		}
+
+		result.index.Servers = append(result.index.Servers, domain.IndexEntry{
+			Name:        server.Name,
+			Path:        p,
+			Description: server.Description,
+			Version:     server.Version,
+		})
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, &SnapshotError{
+			Revision: revision,
+			Errors:   []string{fmt.Sprintf("failed to scan %s: %v", r.scanDir, err)},
+		}
+	}
+
+	sort.Slice(result.index.Servers, func(i, j int) bool {
+		return result.index.Servers[i].Name < result.index.Servers[j].Name
+	})
+
+	return result, nil
+}
+
+// isServerFile reports whether a path looks like a server definition
+func isServerFile(p string) bool {
+	switch path.Ext(p) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// compareIndexes describes how index.yaml differs from the scanned index
+func compareIndexes(file, scanned *domain.Index) []string {
+	fileEntries := make(map[string]domain.IndexEntry, len(file.Servers))
+	for _, e := range file.Servers {
+		fileEntries[e.Name] = e
+	}
+	scannedEntries := make(map[string]domain.IndexEntry, len(scanned.Servers))
+	for _, e := range scanned.Servers {
+		scannedEntries[e.Name] = e
+	}
+
+	var drift []string
+	for _, s := range scanned.Servers {
+		f, ok := fileEntries[s.Name]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("%s: %s is missing from %s", s.Name, s.Path, indexFile))
+			continue
+		}
+		if path.Clean(f.Path) != s.Path {
+			drift = append(drift, fmt.Sprintf("%s: %s points at %s, server file is %s", s.Name, indexFile, f.Path, s.Path))
+		}
+		if f.Version != "" && f.Version != s.Version {
+			drift = append(drift, fmt.Sprintf("%s: %s lists version %s, server file has %s", s.Name, indexFile, f.Version, s.Version))
+		}
+	}
+	for _, f := range file.Servers {
+		if _, ok := scannedEntries[f.Name]; !ok {
+			drift = append(drift, fmt.Sprintf("%s: listed in %s but no server file declares it", f.Name, indexFile))
+		}
+	}
+
+	sort.Strings(drift)
+	return drift
+}
+
+// mergeIndexMetadata copies labels and status overrides, which only exist
+// in index.yaml, onto the scanned entries
+func mergeIndexMetadata(scanned, file *domain.Index) {
+	entries := make(map[string]domain.IndexEntry, len(file.Servers))
+	for _, e := range file.Servers {
+		entries[e.Name] = e
+	}
+	for i := range scanned.Servers {
+		e := entries[scanned.Servers[i].Name]
+		scanned.Servers[i].Labels = e.Labels
+		scanned.Servers[i].Status = e.Status
+	}
+	scanned.Version = file.Version
+}
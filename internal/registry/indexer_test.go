@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"slices"
+	"testing"
+)
+
+// scanFiles is a catalog whose server files and index.yaml disagree
+var scanFiles = map[string]string{
+	"servers/a.yaml":         serverYAML("com.example/a", "1.0.0", "Server A"),
+	"servers/nested/b.json":  `{"$schema":"https://static.modelcontextprotocol.io/schemas/2025-12-11/server.schema.json","name":"com.example/b","description":"Server B","version":"2.0.0"}`,
+	"servers/broken.yaml":    "name: [unterminated\n",
+	"servers/anonymous.yaml": "description: No name\nversion: 1.0.0\n",
+	"servers/notes.txt":      "not a server file\n",
+	"servers-old/c.yaml":     serverYAML("com.example/c", "1.0.0", "Retired server"),
+	"index.yaml": `servers:
+  - name: com.example/a
+    path: servers/a-old.yaml
+    labels:
+      team: platform
+  - name: com.example/b
+    path: servers/nested/b.json
+    version: 1.0.0
+  - name: com.example/c
+    path: servers-old/c.yaml
+`,
+}
+
+func TestScanIndex(t *testing.T) {
+	r := newTestRegistry(t, Config{Store: localStore(t, scanFiles), IndexMode: IndexModeScan})
+
+	var served []string
+	for _, entry := range r.snapshot.Load().Index.Servers {
+		served = append(served, entry.Name+"="+entry.Path)
+	}
+	want := []string{"com.example/a=servers/a.yaml", "com.example/b=servers/nested/b.json"}
+	if !slices.Equal(served, want) {
+		t.Errorf("served %v, want %v", served, want)
+	}
+
+	var quarantined []string
+	for _, q := range r.Quarantined() {
+		quarantined = append(quarantined, q.Path)
+	}
+	slices.Sort(quarantined)
+	if want := []string{"servers/anonymous.yaml", "servers/broken.yaml"}; !slices.Equal(quarantined, want) {
+		t.Errorf("quarantined %v, want %v", quarantined, want)
+	}
+
+	// Scan mode ignores index.yaml entirely
+	if len(r.IndexDrift()) != 0 {
+		t.Errorf("scan mode reported drift %v", r.IndexDrift())
+	}
+	if labels := r.snapshot.Load().labels("com.example/a"); labels != nil {
+		t.Errorf("scan mode served index.yaml labels %v", labels)
+	}
+}
+
+func TestCompareIndex(t *testing.T) {
+	r := newTestRegistry(t, Config{Store: localStore(t, scanFiles), IndexMode: IndexModeCompare})
+
+	if r.ServerCount() != 2 {
+		t.Errorf("served %d servers, want the 2 scanned ones", r.ServerCount())
+	}
+	want := []string{
+		"com.example/a: index.yaml points at servers/a-old.yaml, server file is servers/a.yaml",
+		"com.example/b: index.yaml lists version 1.0.0, server file has 2.0.0",
+		"com.example/c: listed in index.yaml but no server file declares it",
+	}
+	if drift := r.IndexDrift(); !slices.Equal(drift, want) {
+		t.Errorf("drift = %q, want %q", drift, want)
+	}
+	if labels := r.snapshot.Load().labels("com.example/a"); labels["team"] != "platform" {
+		t.Errorf("labels of a = %v, want those of index.yaml", labels)
+	}
+}
+
+func TestScanDir(t *testing.T) {
+	r := newTestRegistry(t, Config{Store: localStore(t, scanFiles), IndexMode: IndexModeScan, ScanDir: "servers-old/"})
+
+	if _, err := r.GetServer("com.example/c"); err != nil || r.ServerCount() != 1 {
+		t.Errorf("scanning servers-old served %d servers, GetServer(c) = %v", r.ServerCount(), err)
+	}
+
+	if _, err := New(Config{Store: localStore(t, scanFiles), ScanDir: "../servers"}); err == nil {
+		t.Error("New accepted a scan directory outside the source")
+	}
+}
@@ -0,0 +1,207 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidSelector is returned for label selectors that cannot be parsed
+var ErrInvalidSelector = errors.New("invalid label selector")
+
+// Label selector operators
+const (
+	opEquals    = "="
+	opNotEquals = "!="
+	opIn        = "in"
+	opNotIn     = "notin"
+	opExists    = "exists"
+	opNotExists = "!"
+)
+
+// Selector is a Kubernetes-style label selector. Every requirement must
+// match; an empty selector matches everything.
+type Selector []requirement
+
+type requirement struct {
+	key    string
+	op     string
+	values []string
+}
+
+// ParseSelector parses comma-separated requirements of the forms
+// key=value, key==value, key!=value, key in (a,b), key notin (a,b),
+// key and !key
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	p := &selectorParser{input: s}
+
+	p.skipSpace()
+	for !p.done() {
+		req, err := p.requirement()
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, req)
+
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected ','")
+		}
+		p.skipSpace()
+		if p.done() {
+			return nil, p.errorf("expected a requirement after ','")
+		}
+	}
+
+	return sel, nil
+}
+
+// Matches reports whether a set of labels satisfies every requirement.
+// As in Kubernetes, != and notin also match servers without the label.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		v, ok := labels[req.key]
+		var match bool
+		switch req.op {
+		case opEquals:
+			match = ok && v == req.values[0]
+		case opNotEquals:
+			match = !ok || v != req.values[0]
+		case opIn:
+			match = ok && slices.Contains(req.values, v)
+		case opNotIn:
+			match = !ok || !slices.Contains(req.values, v)
+		case opExists:
+			match = ok
+		case opNotExists:
+			match = !ok
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+type selectorParser struct {
+	input string
+	pos   int
+}
+
+func (p *selectorParser) requirement() (requirement, error) {
+	if p.consume("!") {
+		p.skipSpace()
+		key, err := p.key()
+		if err != nil {
+			return requirement{}, err
+		}
+		return requirement{key: key, op: opNotExists}, nil
+	}
+
+	key, err := p.key()
+	if err != nil {
+		return requirement{}, err
+	}
+	p.skipSpace()
+
+	switch {
+	case p.done() || p.peek() == ',':
+		return requirement{key: key, op: opExists}, nil
+
+	case p.consume("=="), p.consume("="):
+		p.skipSpace()
+		return requirement{key: key, op: opEquals, values: []string{p.value()}}, nil
+
+	case p.consume("!="):
+		p.skipSpace()
+		return requirement{key: key, op: opNotEquals, values: []string{p.value()}}, nil
+	}
+
+	op := p.word()
+	if op != opIn && op != opNotIn {
+		return requirement{}, p.errorf("expected =, ==, !=, in or notin after %q", key)
+	}
+	values, err := p.set()
+	if err != nil {
+		return requirement{}, err
+	}
+	return requirement{key: key, op: op, values: values}, nil
+}
+
+// set parses a parenthesized, comma-separated list of values
+func (p *selectorParser) set() ([]string, error) {
+	p.skipSpace()
+	if !p.consume("(") {
+		return nil, p.errorf("expected '('")
+	}
+
+	var values []string
+	for {
+		p.skipSpace()
+		values = append(values, p.value())
+		p.skipSpace()
+		if p.consume(")") {
+			return values, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected ',' or ')'")
+		}
+	}
+}
+
+func (p *selectorParser) key() (string, error) {
+	key := p.word()
+	if key == "" {
+		return "", p.errorf("expected a label key")
+	}
+	return key, nil
+}
+
+// value reads a label value, which may be empty
+func (p *selectorParser) value() string {
+	return p.word()
+}
+
+func (p *selectorParser) word() string {
+	start := p.pos
+	for !p.done() && isLabelChar(p.peek()) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func isLabelChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == '/'
+}
+
+func (p *selectorParser) consume(s string) bool {
+	if strings.HasPrefix(p.input[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *selectorParser) skipSpace() {
+	for !p.done() && p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+func (p *selectorParser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *selectorParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *selectorParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at position %d", ErrInvalidSelector, fmt.Sprintf(format, args...), p.pos)
+}
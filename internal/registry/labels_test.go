@@ -0,0 +1,181 @@
+package registry
+
+import (
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Selector
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "   ", want: nil},
+		{input: "tier=gold", want: Selector{{key: "tier", op: opEquals, values: []string{"gold"}}}},
+		{input: "tier==gold", want: Selector{{key: "tier", op: opEquals, values: []string{"gold"}}}},
+		{input: "tier = gold", want: Selector{{key: "tier", op: opEquals, values: []string{"gold"}}}},
+		{input: "tier=", want: Selector{{key: "tier", op: opEquals, values: []string{""}}}},
+		{input: "tier!=gold", want: Selector{{key: "tier", op: opNotEquals, values: []string{"gold"}}}},
+		{input: "team", want: Selector{{key: "team", op: opExists}}},
+		{input: "!team", want: Selector{{key: "team", op: opNotExists}}},
+		{input: "! team", want: Selector{{key: "team", op: opNotExists}}},
+		{
+			input: "env in (prod, staging)",
+			want:  Selector{{key: "env", op: opIn, values: []string{"prod", "staging"}}},
+		},
+		{
+			input: "env notin (dev)",
+			want:  Selector{{key: "env", op: opNotIn, values: []string{"dev"}}},
+		},
+		{
+			input: "example.com/owner=infra,team,!legacy",
+			want: Selector{
+				{key: "example.com/owner", op: opEquals, values: []string{"infra"}},
+				{key: "team", op: opExists},
+				{key: "legacy", op: opNotExists},
+			},
+		},
+		{input: "=gold", wantErr: true},
+		{input: "tier=gold,", wantErr: true},
+		{input: ",tier", wantErr: true},
+		{input: "tier gold", wantErr: true},
+		{input: "env in prod", wantErr: true},
+		{input: "env in (prod", wantErr: true},
+		{input: "env in (prod staging)", wantErr: true},
+		{input: "tier=gold;team", wantErr: true},
+		{input: "!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSelector(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSelector) {
+					t.Fatalf("ParseSelector(%q) error = %v, want ErrInvalidSelector", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSelector(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSelector(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	labels := map[string]string{"tier": "gold", "env": "prod"}
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{selector: "", want: true},
+		{selector: "tier=gold", want: true},
+		{selector: "tier=silver", want: false},
+		{selector: "tier!=silver", want: true},
+		{selector: "team!=infra", want: true},
+		{selector: "env in (prod,staging)", want: true},
+		{selector: "env notin (prod)", want: false},
+		{selector: "team notin (infra)", want: true},
+		{selector: "team in (infra)", want: false},
+		{selector: "tier", want: true},
+		{selector: "!tier", want: false},
+		{selector: "!team", want: true},
+		{selector: "tier=gold,env=dev", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, err := ParseSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("ParseSelector(%q) error = %v", tt.selector, err)
+			}
+			if got := sel.Matches(labels); got != tt.want {
+				t.Errorf("%q matches %v = %v, want %v", tt.selector, labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListServersByLabels(t *testing.T) {
+	files := map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/billing
+    path: servers/billing.yaml
+    labels:
+      team: platform
+      env: prod
+  - name: com.example/canary
+    path: servers/canary.yaml
+    labels:
+      team: platform
+      env: staging
+      tier: experimental
+  - name: com.example/scratch
+    path: servers/scratch.yaml
+`,
+		"servers/billing.yaml": serverYAML("com.example/billing", "1.0.0", "Billing"),
+		"servers/canary.yaml":  serverYAML("com.example/canary", "1.0.0", "Canary"),
+		"servers/scratch.yaml": serverYAML("com.example/scratch", "1.0.0", "Unlabeled"),
+	}
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{selector: "team=platform", want: []string{"com.example/billing", "com.example/canary"}},
+		{selector: "team=platform,tier!=experimental", want: []string{"com.example/billing"}},
+		{selector: "env in (prod,staging)", want: []string{"com.example/billing", "com.example/canary"}},
+		{selector: "!team", want: []string{"com.example/scratch"}},
+		{selector: "env notin (prod)", want: []string{"com.example/canary", "com.example/scratch"}},
+	}
+
+	for _, mode := range []string{CacheModePreload, CacheModeLazy} {
+		r := newTestRegistry(t, Config{Store: localStore(t, files), CacheMode: mode})
+
+		for _, tt := range tests {
+			t.Run(mode+"/"+tt.selector, func(t *testing.T) {
+				sel, err := ParseSelector(tt.selector)
+				if err != nil {
+					t.Fatal(err)
+				}
+				list, err := r.ListServers(ListOptions{Labels: sel})
+				if err != nil {
+					t.Fatalf("ListServers: %v", err)
+				}
+				var got []string
+				for _, s := range list.Servers {
+					got = append(got, s.Server.Name)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("listed %v, want %v", got, tt.want)
+				}
+			})
+		}
+
+		t.Run(mode+"/meta", func(t *testing.T) {
+			list, err := r.ListServers(ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]map[string]string{
+				"com.example/billing": {"team": "platform", "env": "prod"},
+				"com.example/canary":  {"team": "platform", "env": "staging", "tier": "experimental"},
+				"com.example/scratch": nil,
+			}
+			for _, s := range list.Servers {
+				if !maps.Equal(s.Meta.Labels, want[s.Server.Name]) {
+					t.Errorf("%s _meta labels = %v, want %v", s.Server.Name, s.Meta.Labels, want[s.Server.Name])
+				}
+			}
+		})
+	}
+}
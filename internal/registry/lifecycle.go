@@ -0,0 +1,114 @@
+package registry
+
+import (
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// statusDater dates the status blocks of one snapshot that do not set
+// since, from the commit that introduced their current state: in index.yaml
+// for statuses set there, in the definition file otherwise. Sources without
+// history date a state from the first snapshot that saw it.
+type statusDater struct {
+	r        *Registry
+	revision string
+	prev     *Snapshot
+	now      time.Time
+
+	// states of index.yaml entries by name, per revision newest first,
+	// read on first use
+	index     []indexStates
+	indexRead bool
+}
+
+type indexStates struct {
+	time   time.Time
+	states map[string]string
+}
+
+// since returns when a server's status entered its current state
+func (d *statusDater) since(entry domain.IndexEntry, state string) time.Time {
+	if d.prev != nil {
+		if st := d.prev.statuses[entry.Name]; st != nil && st.State == state {
+			if t, ok := d.prev.datedStatus[entry.Name]; ok {
+				return t
+			}
+		}
+	}
+
+	history, ok := d.r.store.(source.History)
+	if !ok {
+		return d.now
+	}
+
+	var t time.Time
+	if entry.Status != nil {
+		t = d.introducedInIndex(history, entry.Name, state)
+	} else {
+		t = d.introducedInFile(history, entry.Path, state)
+	}
+	if t.IsZero() {
+		return d.now
+	}
+	return t
+}
+
+// introducedInFile walks the history of a definition back to the commit
+// that set its status block to state
+func (d *statusDater) introducedInFile(history source.History, path, state string) time.Time {
+	revisions, err := history.FileHistory(d.revision, path)
+	if err != nil {
+		d.r.logger.Debug("failed to read status history", "path", path, "error", err)
+		return time.Time{}
+	}
+
+	var introduced time.Time
+	for _, rev := range revisions {
+		var def struct {
+			Status *domain.ServerStatus `yaml:"status"`
+		}
+		if err := yaml.Unmarshal(rev.Content, &def); err != nil || def.Status == nil || def.Status.State != state {
+			break
+		}
+		introduced = rev.Time
+	}
+	return introduced
+}
+
+// introducedInIndex walks the history of index.yaml back to the commit that
+// set a server's status to state
+func (d *statusDater) introducedInIndex(history source.History, name, state string) time.Time {
+	if !d.indexRead {
+		d.indexRead = true
+		revisions, err := history.FileHistory(d.revision, indexFile)
+		if err != nil {
+			d.r.logger.Debug("failed to read status history", "path", indexFile, "error", err)
+		}
+		for _, rev := range revisions {
+			var index domain.Index
+			if err := yaml.Unmarshal(rev.Content, &index); err != nil {
+				break
+			}
+			states := make(map[string]string)
+			for _, e := range index.Servers {
+				if e.Status != nil {
+					states[e.Name] = e.Status.State
+				}
+			}
+			d.index = append(d.index, indexStates{time: rev.Time, states: states})
+		}
+	}
+
+	var introduced time.Time
+	for _, rev := range d.index {
+		if rev.states[name] != state {
+			break
+		}
+		introduced = rev.time
+	}
+	return introduced
+}
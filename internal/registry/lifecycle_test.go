@@ -0,0 +1,95 @@
+package registry
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+const lifecycleIndex = `servers:
+  - name: com.example/legacy
+    path: servers/legacy.yaml
+    status:
+      state: deleted
+      message: Superseded
+  - name: com.example/weather
+    path: servers/weather.yaml
+`
+
+func TestLifecycleStatus(t *testing.T) {
+	repo := newGitFixture(t)
+	hour := func(n int) time.Time { return fixtureStart.Add(time.Duration(n) * time.Hour) }
+	deprecated := func(message string) string {
+		return serverYAML("com.example/weather", "1.0.0", "Weather") + `status:
+  state: deprecated
+  message: ` + message + `
+  replacement: com.example/legacy
+  sunset: 2026-01-01T00:00:00Z
+`
+	}
+
+	repo.commit(map[string]string{
+		"index.yaml":           lifecycleIndex,
+		"servers/legacy.yaml":  serverYAML("com.example/legacy", "1.0.0", "Legacy weather"),
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.0.0", "Weather"),
+	})
+	repo.commit(map[string]string{"servers/weather.yaml": deprecated("Moving on")})
+	// Editing the block without changing the state keeps its date
+	repo.commit(map[string]string{"servers/weather.yaml": deprecated("Moving on soon")})
+
+	r := newTestRegistry(t, Config{Store: repo.store()})
+
+	weather := r.ServerStatus("com.example/weather")
+	if weather == nil || weather.State != domain.StatusDeprecated || weather.Message != "Moving on soon" {
+		t.Fatalf("weather status = %+v, want deprecated", weather)
+	}
+	if weather.Since == nil || !weather.Since.Equal(hour(1)) {
+		t.Errorf("weather deprecated since %v, want %v", weather.Since, hour(1))
+	}
+	if weather.Sunset == nil || !weather.Sunset.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("weather sunset = %v", weather.Sunset)
+	}
+	legacy := r.ServerStatus("com.example/legacy")
+	if legacy == nil || legacy.State != domain.StatusDeleted || legacy.Since == nil || !legacy.Since.Equal(hour(0)) {
+		t.Errorf("legacy status = %+v, want deleted since %v", legacy, hour(0))
+	}
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want []string
+	}{
+		{name: "listing", want: []string{"com.example/weather"}},
+		{name: "listing with deleted", opts: ListOptions{IncludeDeleted: true}, want: []string{"com.example/legacy", "com.example/weather"}},
+		{name: "search", opts: ListOptions{Search: "weather"}, want: []string{"com.example/weather"}},
+		{name: "search with deleted", opts: ListOptions{Search: "weather", IncludeDeleted: true}, want: []string{"com.example/weather", "com.example/legacy"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := r.ListServers(tt.opts)
+			if err != nil {
+				t.Fatalf("ListServers: %v", err)
+			}
+			var got []string
+			for _, s := range list.Servers {
+				got = append(got, s.Server.Name)
+				if s.Server.Name == "com.example/legacy" && s.Meta.Official.Status != domain.StatusDeleted {
+					t.Errorf("legacy listed as %q", s.Meta.Official.Status)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("listed %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Tombstones still resolve by name but are not counted
+	if _, err := r.GetServer("com.example/legacy"); err != nil {
+		t.Errorf("GetServer(legacy): %v", err)
+	}
+	if facets := r.Facets(); facets.Total != 1 {
+		t.Errorf("facets count %d servers, want the deleted one left out", facets.Total)
+	}
+}
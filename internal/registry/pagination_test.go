@@ -0,0 +1,99 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// catalogFiles lists n servers in index.yaml in reverse name order, so
+// pages only come out sorted if the registry sorts them
+func catalogFiles(n int) map[string]string {
+	files := make(map[string]string, n+1)
+	var index strings.Builder
+	index.WriteString("servers:\n")
+	for i := n - 1; i >= 0; i-- {
+		name := fmt.Sprintf("com.example/server-%03d", i)
+		path := fmt.Sprintf("servers/server-%03d.yaml", i)
+		fmt.Fprintf(&index, "  - name: %s\n    path: %s\n", name, path)
+		files[path] = serverYAML(name, "1.0.0", fmt.Sprintf("Server number %d", i))
+	}
+	files["index.yaml"] = index.String()
+	return files
+}
+
+// listAll follows cursors from the first page to the last
+func listAll(t *testing.T, list func(ListOptions) (*domain.ServerListResponse, error), limit int) []string {
+	t.Helper()
+	var names []string
+	opts := ListOptions{Limit: limit}
+	for pages := 0; ; pages++ {
+		if pages > 100 {
+			t.Fatal("pagination does not terminate")
+		}
+		page, err := list(opts)
+		if err != nil {
+			t.Fatalf("list page %d: %v", pages, err)
+		}
+		if page.Metadata.Count != len(page.Servers) {
+			t.Errorf("page %d count = %d, holds %d servers", pages, page.Metadata.Count, len(page.Servers))
+		}
+		for _, s := range page.Servers {
+			names = append(names, s.Server.Name)
+		}
+		if page.Metadata.NextCursor == "" {
+			return names
+		}
+		opts.Cursor = page.Metadata.NextCursor
+	}
+}
+
+func TestPagination(t *testing.T) {
+	const total = 65
+	files := catalogFiles(total)
+	var want []string
+	for i := range total {
+		want = append(want, fmt.Sprintf("com.example/server-%03d", i))
+	}
+
+	for _, mode := range []string{CacheModePreload, CacheModeLazy} {
+		r := newTestRegistry(t, Config{Store: localStore(t, files), CacheMode: mode})
+
+		listJSON := func(opts ListOptions) (*domain.ServerListResponse, error) {
+			content, err := r.ListServersJSON(opts)
+			if err != nil {
+				return nil, err
+			}
+			var resp domain.ServerListResponse
+			return &resp, json.Unmarshal(content, &resp)
+		}
+
+		// The default page size is the one preload mode pre-serializes
+		for _, limit := range []int{0, 7, 100} {
+			t.Run(fmt.Sprintf("%s/limit %d", mode, limit), func(t *testing.T) {
+				if got := listAll(t, r.ListServers, limit); !slices.Equal(got, want) {
+					t.Errorf("ListServers pages = %v, want %v", got, want)
+				}
+				if got := listAll(t, listJSON, limit); !slices.Equal(got, want) {
+					t.Errorf("ListServersJSON pages = %v, want %v", got, want)
+				}
+			})
+		}
+
+		t.Run(mode+"/lookup", func(t *testing.T) {
+			for _, name := range want {
+				server, err := r.GetServer(name)
+				if err != nil || server.Name != name {
+					t.Fatalf("GetServer(%s) = %v, %v", name, server, err)
+				}
+			}
+			if _, err := r.GetServer("com.example/server-999"); err == nil {
+				t.Error("GetServer found a server that is not in the index")
+			}
+		})
+	}
+}
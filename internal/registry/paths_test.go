@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIndexPathsConfined(t *testing.T) {
+	repo := newGitFixture(t)
+	repo.commit(map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/ok
+    path: ./servers/ok.yaml
+  - name: com.example/parent
+    path: ../outside.yaml
+  - name: com.example/absolute
+    path: /etc/passwd
+  - name: com.example/link
+    path: servers/link.yaml
+  - name: com.example/directory
+    path: servers
+`,
+		"servers/ok.yaml": serverYAML("com.example/ok", "1.0.0", "Confined server"),
+		"README.md":       serverYAML("com.example/link", "1.0.0", "Outside the servers directory"),
+	})
+	repo.symlink("servers/link.yaml", "../README.md")
+
+	r := newTestRegistry(t, Config{Store: repo.store()})
+
+	if _, err := r.GetServer("com.example/ok"); err != nil {
+		t.Errorf("GetServer(ok): %v", err)
+	}
+	var quarantined []string
+	for _, q := range r.Quarantined() {
+		quarantined = append(quarantined, q.Name)
+	}
+	want := []string{"com.example/parent", "com.example/absolute", "com.example/link", "com.example/directory"}
+	if !slices.Equal(quarantined, want) {
+		t.Errorf("quarantined %v, want %v", quarantined, want)
+	}
+	for _, name := range want {
+		if _, err := r.GetServer(name); err == nil {
+			t.Errorf("GetServer(%s) served a quarantined entry", name)
+		}
+	}
+}
+
+func TestScanSkipsSymlinks(t *testing.T) {
+	repo := newGitFixture(t)
+	repo.commit(map[string]string{
+		"servers/ok.yaml": serverYAML("com.example/ok", "1.0.0", "Confined server"),
+	})
+	// Followed, the link would declare the same server twice and reject the
+	// whole revision
+	repo.symlink("servers/alias.yaml", "ok.yaml")
+
+	r := newTestRegistry(t, Config{Store: repo.store(), IndexMode: IndexModeScan})
+
+	if r.ServerCount() != 1 || len(r.Quarantined()) != 0 {
+		t.Errorf("scanned %d servers, quarantined %v; want only servers/ok.yaml", r.ServerCount(), r.Quarantined())
+	}
+}
@@ -5,41 +5,76 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
-	"sort"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	lru "github.com/hashicorp/golang-lru/v2"
-	"gopkg.in/yaml.v3"
 
 	"github.com/mcpregistry/server/internal/domain"
-	"github.com/mcpregistry/server/internal/gitstore"
+	"github.com/mcpregistry/server/internal/middleware"
+	"github.com/mcpregistry/server/internal/schema"
+	"github.com/mcpregistry/server/internal/source"
 )
 
 // Registry provides access to MCP server definitions
 type Registry struct {
-	store     *gitstore.Store
-	cache     *lru.Cache[string, *domain.ServerJSON]
-	index     *domain.Index
-	indexMu   sync.RWMutex
-	cacheSize int
-	logger    *slog.Logger
+	store      source.Source
+	cache      *lru.Cache[string, *cachedServer]
+	negative   *lru.Cache[string, string] // revision each unknown name was looked up at
+	loads      flightGroup[*domain.ServerJSON]
+	history    *lru.Cache[string, []domain.ServerVersion] // by historyKey
+	snapshot   atomic.Pointer[Snapshot]
+	rejection  atomic.Pointer[domain.SyncRejection]
+	loadMu     sync.Mutex
+	retention  int
+	retained   []*Snapshot // oldest first, for cursors
+	retainedMu sync.Mutex
+	syncs      []domain.SyncDiff // oldest first
+	syncsMu    sync.Mutex
+	cacheSize  int
+	cacheMode  string
+	indexMode  string
+	scanDir    string
+	schemas    *schema.Validator
+	logger     *slog.Logger
+
+	validationPolicy string
 
 	// Stats
-	cacheHits   atomic.Int64
-	cacheMisses atomic.Int64
-	lastSyncAt  atomic.Value // time.Time
+	cacheHits      atomic.Int64
+	cacheMisses    atomic.Int64
+	cacheCoalesced atomic.Int64
+	negativeHits   atomic.Int64
+	cachedBytes    atomic.Int64
+	lastSyncAt     atomic.Value // time.Time
 }
 
 // Config holds registry configuration
 type Config struct {
-	Store     *gitstore.Store
-	CacheSize int
+	Store     source.Source
+	CacheSize int    // LRU capacity in lazy cache mode, and of the negative cache
+	CacheMode string // CacheModePreload (default) or CacheModeLazy
+	IndexMode string // IndexModeFile (default), IndexModeScan or IndexModeCompare
+	ScanDir   string // directory scanned for server files, default "servers"
 	Logger    *slog.Logger
+
+	// ValidationPolicy is ValidationLenient (default) or ValidationStrict
+	ValidationPolicy string
+
+	// SnapshotRetention is how many snapshots, the served one included,
+	// pagination cursors keep reading from. Default 3.
+	SnapshotRetention int
 }
 
+// Validation policies
+const (
+	// ValidationLenient serves invalid servers and flags them in _meta
+	ValidationLenient = "lenient"
+	// ValidationStrict excludes invalid servers from the snapshot
+	ValidationStrict = "strict"
+)
+
 // New creates a new registry instance
 func New(cfg Config) (*Registry, error) {
 	if cfg.Store == nil {
@@ -48,65 +83,179 @@ func New(cfg Config) (*Registry, error) {
 	if cfg.CacheSize <= 0 {
 		cfg.CacheSize = 1000
 	}
+	if cfg.SnapshotRetention <= 0 {
+		cfg.SnapshotRetention = 3
+	}
+	switch cfg.CacheMode {
+	case "":
+		cfg.CacheMode = CacheModePreload
+	case CacheModePreload, CacheModeLazy:
+	default:
+		return nil, fmt.Errorf("unknown cache mode %q", cfg.CacheMode)
+	}
+	switch cfg.IndexMode {
+	case "":
+		cfg.IndexMode = IndexModeFile
+	case IndexModeFile, IndexModeScan, IndexModeCompare:
+	default:
+		return nil, fmt.Errorf("unknown index mode %q", cfg.IndexMode)
+	}
+	if cfg.ScanDir == "" {
+		cfg.ScanDir = "servers"
+	}
+	switch cfg.ValidationPolicy {
+	case "":
+		cfg.ValidationPolicy = ValidationLenient
+	case ValidationLenient, ValidationStrict:
+	default:
+		return nil, fmt.Errorf("unknown validation policy %q", cfg.ValidationPolicy)
+	}
+	scanDir, err := source.CleanPath(cfg.ScanDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan directory: %w", err)
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = slog.Default()
 	}
 
-	cache, err := lru.New[string, *domain.ServerJSON](cfg.CacheSize)
+	history, err := lru.New[string, []domain.ServerVersion](cfg.CacheSize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create LRU cache: %w", err)
+		return nil, fmt.Errorf("failed to create history cache: %w", err)
+	}
+
+	negative, err := lru.New[string, string](cfg.CacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create negative cache: %w", err)
+	}
+
+	schemas, err := schema.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server schemas: %w", err)
 	}
 
 	r := &Registry{
 		store:     cfg.Store,
-		cache:     cache,
+		history:   history,
+		negative:  negative,
 		cacheSize: cfg.CacheSize,
+		cacheMode: cfg.CacheMode,
+		retention: cfg.SnapshotRetention,
+		indexMode: cfg.IndexMode,
+		scanDir:   scanDir,
+		schemas:   schemas,
 		logger:    cfg.Logger,
+
+		validationPolicy: cfg.ValidationPolicy,
 	}
 	r.lastSyncAt.Store(time.Time{})
 
+	r.cache, err = lru.NewWithEvict(cfg.CacheSize, func(_ string, v *cachedServer) {
+		r.cachedBytes.Add(-v.size)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LRU cache: %w", err)
+	}
+
 	return r, nil
 }
 
-// LoadIndex loads and validates the index.yaml file
+// LoadIndex builds a snapshot of the current revision and swaps it in.
+// If the revision is invalid the previous snapshot stays live and the
+// rejection is recorded for health reporting.
 func (r *Registry) LoadIndex() error {
-	r.indexMu.Lock()
-	defer r.indexMu.Unlock()
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
 
-	content, err := r.store.ReadFile("index.yaml")
+	snap, err := r.buildSnapshot()
 	if err != nil {
-		return fmt.Errorf("index.yaml not found: %w", err)
+		var snapErr *SnapshotError
+		if errors.As(err, &snapErr) {
+			r.rejection.Store(&domain.SyncRejection{
+				Revision:   snapErr.Revision,
+				RejectedAt: time.Now(),
+				Errors:     snapErr.Errors,
+			})
+		}
+		if current := r.snapshot.Load(); current != nil {
+			r.logger.Error("rejected new revision, keeping last known good snapshot",
+				"serving", current.Revision,
+				"error", err,
+			)
+		}
+		return err
 	}
 
-	var index domain.Index
-	if err := yaml.Unmarshal(content, &index); err != nil {
-		return fmt.Errorf("failed to parse index.yaml: %w", err)
+	if len(snap.Index.Servers) == 0 {
+		if r.indexMode == IndexModeFile {
+			r.logger.Warn("index.yaml contains no servers")
+		} else {
+			r.logger.Warn("no server files found", "scan_dir", r.scanDir)
+		}
 	}
-
-	if len(index.Servers) == 0 {
-		r.logger.Warn("index.yaml contains no servers")
+	for _, v := range snap.Validation.Servers {
+		if !v.Valid {
+			r.logger.Warn("server failed validation",
+				"name", v.Name,
+				"issues", len(v.Issues),
+				"excluded", v.Excluded,
+			)
+		}
+	}
+	for _, q := range snap.Quarantined {
+		r.logger.Warn("quarantined index entry",
+			"name", q.Name,
+			"path", q.Path,
+			"reason", q.Reason,
+		)
 	}
 
-	r.index = &index
-	r.lastSyncAt.Store(time.Now())
+	r.snapshot.Store(snap)
+	r.retainSnapshot(snap)
+	r.rejection.Store(nil)
+	r.lastSyncAt.Store(snap.LoadedAt)
+	middleware.RegistryIndexDrift.Set(float64(len(snap.Drift)))
 
 	r.logger.Info("index loaded",
-		"version", index.Version,
-		"commit", index.Commit,
-		"server_count", len(index.Servers),
+		"version", snap.Index.Version,
+		"commit", snap.Index.Commit,
+		"revision", snap.Revision,
+		"server_count", len(snap.Index.Servers),
+		"quarantined", len(snap.Quarantined),
+		"index_mode", r.indexMode,
+		"drift", len(snap.Drift),
+		"invalid", snap.Validation.Invalid,
 	)
 
 	return nil
 }
 
-// Refresh reloads the index and invalidates cache
-func (r *Registry) Refresh() error {
-	// Clear cache before reload
-	r.cache.Purge()
-	r.cacheHits.Store(0)
-	r.cacheMisses.Store(0)
+// Refresh rebuilds the snapshot and, once it is live, invalidates cached
+// data of the servers whose files or index entries changed. Cache stats
+// accumulate across syncs. It returns what changed, or nil for the first
+// snapshot.
+func (r *Registry) Refresh() (*domain.SyncDiff, error) {
+	if err := r.LoadIndex(); err != nil {
+		return nil, err
+	}
 
-	return r.LoadIndex()
+	snap := r.snapshot.Load()
+	if snap.diff == nil {
+		return nil, nil
+	}
+	r.invalidate(snap.diff)
+	r.recordSync(snap.diff)
+
+	r.logger.Info("caches invalidated",
+		"from", snap.diff.From,
+		"to", snap.diff.To,
+		"changed_paths", len(snap.diff.Paths),
+		"added", len(snap.diff.Added),
+		"removed", len(snap.diff.Removed),
+		"modified", len(snap.diff.Modified),
+		"invalidated", snap.diff.Invalidated,
+	)
+
+	return snap.diff, nil
 }
 
 // GetServer retrieves a server by name
@@ -117,114 +266,236 @@ func (r *Registry) GetServer(name string) (*domain.ServerJSON, error) {
 		decodedName = name
 	}
 
-	// Check cache first
-	if server, ok := r.cache.Get(decodedName); ok {
-		r.cacheHits.Add(1)
-		return server, nil
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil, errors.New("index not loaded")
+	}
+
+	return r.server(snap, decodedName)
+}
+
+// ServerResponseJSON returns the encoded response for a server's latest
+// version, pre-serialized in preload mode
+func (r *Registry) ServerResponseJSON(name string) ([]byte, error) {
+	decodedName, err := url.PathUnescape(name)
+	if err != nil {
+		decodedName = name
+	}
+
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil, errors.New("index not loaded")
 	}
-	r.cacheMisses.Add(1)
 
-	// Find in index
-	r.indexMu.RLock()
-	if r.index == nil {
-		r.indexMu.RUnlock()
+	if snap.encoded != nil {
+		body, ok := snap.encoded.servers[decodedName]
+		if !ok {
+			return nil, r.unknownServer(snap.Revision, decodedName)
+		}
+		r.recordHit()
+		return body, nil
+	}
+
+	server, err := r.server(snap, decodedName)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(snap.serverResponse(server))
+}
+
+// GetServerVersions returns every version of a server found in the git
+// history of its definition file, newest first
+func (r *Registry) GetServerVersions(name string) ([]domain.ServerVersion, error) {
+	decodedName, err := url.PathUnescape(name)
+	if err != nil {
+		decodedName = name
+	}
+
+	snap := r.snapshot.Load()
+	if snap == nil {
 		return nil, errors.New("index not loaded")
 	}
 
-	var entry *domain.IndexEntry
-	for i := range r.index.Servers {
-		if r.index.Servers[i].Name == decodedName {
-			entry = &r.index.Servers[i]
-			break
+	key := historyKey(snap.Revision, decodedName)
+	if versions, ok := r.history.Get(key); ok {
+		return versions, nil
+	}
+
+	history, ok := r.store.(source.History)
+	if !ok {
+		// Sources without history only know the current definition
+		server, err := r.server(snap, decodedName)
+		if err != nil {
+			return nil, err
 		}
+		change, _ := snap.change(decodedName)
+		return []domain.ServerVersion{{
+			Info: domain.VersionInfo{
+				Version:     server.Version,
+				PublishedAt: change.updated,
+				Commit:      change.commit,
+				IsLatest:    true,
+			},
+			Server: *server,
+		}}, nil
 	}
-	r.indexMu.RUnlock()
 
-	if entry == nil {
+	entry, ok := snap.entry(decodedName)
+	if !ok {
 		return nil, fmt.Errorf("server not found: %s", decodedName)
 	}
 
-	// Load from disk
-	content, err := r.store.ReadFile(entry.Path)
+	revisions, err := history.FileHistory(snap.Revision, entry.Path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read server file: %w", err)
+		return nil, fmt.Errorf("failed to read server history: %w", err)
+	}
+
+	var versions []domain.ServerVersion
+	seen := make(map[string]int)
+	for _, rev := range revisions {
+		parsed, err := parseServer(rev.Content)
+		if err != nil {
+			r.logger.Debug("skipping unparseable server revision",
+				"name", decodedName,
+				"commit", rev.Commit,
+				"error", err,
+			)
+			continue
+		}
+		server := parsed.server
+
+		// Revisions are newest first, so an already seen version was
+		// first published by this older commit
+		if i, ok := seen[server.Version]; ok {
+			versions[i].Info.PublishedAt = rev.Time
+			continue
+		}
+
+		seen[server.Version] = len(versions)
+		versions = append(versions, domain.ServerVersion{
+			Info: domain.VersionInfo{
+				Version:     server.Version,
+				PublishedAt: rev.Time,
+				Commit:      rev.Commit,
+			},
+			Server: *server,
+		})
 	}
 
-	var server domain.ServerJSON
-	if err := yaml.Unmarshal(content, &server); err != nil {
-		return nil, fmt.Errorf("failed to parse server file: %w", err)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no version history for server: %s", decodedName)
 	}
+	versions[0].Info.IsLatest = true
 
-	// Add to cache
-	r.cache.Add(decodedName, &server)
+	r.history.Add(key, versions)
 
-	return &server, nil
+	return versions, nil
 }
 
-// ListServers returns a paginated list of servers
-func (r *Registry) ListServers(cursor string, limit int) (*domain.ServerListResponse, error) {
-	r.indexMu.RLock()
-	defer r.indexMu.RUnlock()
+// historyKey keys version histories by the revision they were read at, so
+// a history read before a sync can never be served after it
+func historyKey(revision, name string) string {
+	return revision + "\x00" + name
+}
 
-	if r.index == nil {
-		return nil, errors.New("index not loaded")
+// GetServerVersion returns a specific version of a server.
+// The version "latest" resolves to the newest one.
+func (r *Registry) GetServerVersion(name, version string) (*domain.ServerVersion, error) {
+	versions, err := r.GetServerVersions(name)
+	if err != nil {
+		return nil, err
 	}
 
-	if limit <= 0 {
-		limit = 30
+	if version == "latest" {
+		return &versions[0], nil
 	}
-	if limit > 100 {
-		limit = 100
+
+	for i := range versions {
+		if versions[i].Info.Version == version {
+			return &versions[i], nil
+		}
 	}
 
-	// Sort servers by name for consistent pagination
-	servers := make([]domain.IndexEntry, len(r.index.Servers))
-	copy(servers, r.index.Servers)
-	sort.Slice(servers, func(i, j int) bool {
-		return servers[i].Name < servers[j].Name
-	})
+	return nil, fmt.Errorf("version not found: %s", version)
+}
+
+// ListOptions selects a page of servers
+type ListOptions struct {
+	Cursor string
+	Limit  int
+	Search string // full-text query; results are ranked by relevance
+	Filter Filter
+	Labels Selector // matched against index.yaml labels
 
-	// Find start position
-	startIdx := 0
-	if cursor != "" {
-		for i, s := range servers {
-			if s.Name == cursor {
-				startIdx = i + 1
-				break
-			}
+	// UpdatedSince keeps servers whose definition changed after this time
+	UpdatedSince time.Time
+
+	// IncludeDeleted lists tombstoned servers, which are hidden by default
+	IncludeDeleted bool
+}
+
+// ListServers returns a paginated list of servers. Cursors continue on the
+// snapshot they were issued for while it is retained; see resolvePage.
+func (r *Registry) ListServers(opts ListOptions) (*domain.ServerListResponse, error) {
+	snap, sel, start, err := r.resolvePage(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.listServers(snap, sel, start, pageLimit(opts.Limit))
+}
+
+// ListServersJSON returns an encoded page of servers. In preload mode
+// default-sized pages of the full list are served pre-serialized and other
+// pages are assembled from pre-serialized entries.
+func (r *Registry) ListServersJSON(opts ListOptions) ([]byte, error) {
+	snap, sel, start, err := r.resolvePage(opts)
+	if err != nil {
+		return nil, err
+	}
+	limit := pageLimit(opts.Limit)
+
+	if snap.encoded != nil {
+		if sel.listed && limit == defaultPageSize && start%defaultPageSize == 0 &&
+			start/defaultPageSize < len(snap.encoded.pages) {
+			return snap.encoded.pages[start/defaultPageSize], nil
 		}
+		return snap.page(snap.encoded.items, sel, start, limit)
 	}
 
-	// Collect results
-	var results []domain.ServerResponse
-	endIdx := startIdx + limit
-	if endIdx > len(servers) {
-		endIdx = len(servers)
+	resp, err := r.listServers(snap, sel, start, limit)
+	if err != nil {
+		return nil, err
 	}
+	return encodeJSON(resp)
+}
 
-	for i := startIdx; i < endIdx; i++ {
-		entry := servers[i]
+// pageLimit clamps a requested page size
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	return min(limit, 100)
+}
 
-		// Try to get from cache, otherwise use index info
-		server, err := r.GetServer(entry.Name)
+func (r *Registry) listServers(snap *Snapshot, sel selection, startIdx, limit int) (*domain.ServerListResponse, error) {
+	servers := snap.Index.Servers
+	total := sel.len(snap)
+	endIdx := min(startIdx+limit, total)
+
+	results := make([]domain.ServerResponse, 0, endIdx-startIdx)
+	for i := startIdx; i < endIdx; i++ {
+		server, err := r.server(snap, servers[sel.at(i)].Name)
 		if err != nil {
-			// Use minimal info from index if file load fails
-			server = &domain.ServerJSON{
-				Name:        entry.Name,
-				Description: entry.Description,
-				Version:     entry.Version,
-			}
+			return nil, err
 		}
-
-		results = append(results, domain.ServerResponse{
-			Server: *server,
-		})
+		results = append(results, snap.serverResponse(server))
 	}
 
 	// Determine next cursor
 	var nextCursor string
-	if endIdx < len(servers) {
-		nextCursor = servers[endIdx-1].Name
+	if endIdx < total {
+		nextCursor = cursor{Revision: snap.Revision, Position: endIdx, Query: sel.query}.encode()
 	}
 
 	return &domain.ServerListResponse{
@@ -236,51 +507,158 @@ func (r *Registry) ListServers(cursor string, limit int) (*domain.ServerListResp
 	}, nil
 }
 
-// SearchServers searches for servers matching a query
+// SearchServers returns the index entries matching a full-text query,
+// most relevant first
 func (r *Registry) SearchServers(query string) ([]domain.IndexEntry, error) {
-	r.indexMu.RLock()
-	defer r.indexMu.RUnlock()
-
-	if r.index == nil {
+	snap := r.snapshot.Load()
+	if snap == nil {
 		return nil, errors.New("index not loaded")
 	}
 
-	query = strings.ToLower(query)
-	var results []domain.IndexEntry
-
-	for _, entry := range r.index.Servers {
-		if strings.Contains(strings.ToLower(entry.Name), query) ||
-			strings.Contains(strings.ToLower(entry.Description), query) {
-			results = append(results, entry)
-		}
+	positions := snap.search.search(query)
+	results := make([]domain.IndexEntry, 0, len(positions))
+	for _, p := range positions {
+		results = append(results, snap.Index.Servers[p])
 	}
 
 	return results, nil
 }
 
+// Facets returns the number of servers per facet value in the served snapshot
+func (r *Registry) Facets() *domain.FacetsResponse {
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil
+	}
+	return snap.facetCounts
+}
+
 // ServerCount returns the number of servers in the index
 func (r *Registry) ServerCount() int {
-	r.indexMu.RLock()
-	defer r.indexMu.RUnlock()
-
-	if r.index == nil {
+	snap := r.snapshot.Load()
+	if snap == nil {
 		return 0
 	}
-	return len(r.index.Servers)
+	return len(snap.Index.Servers)
 }
 
 // IndexStatus returns the current index status
 func (r *Registry) IndexStatus() string {
-	r.indexMu.RLock()
-	defer r.indexMu.RUnlock()
-
-	if r.index == nil {
+	if r.snapshot.Load() == nil {
 		return "not_loaded"
 	}
 	return "valid"
 }
 
-// CacheStats returns current cache statistics
+// Revision returns the revision of the snapshot being served
+func (r *Registry) Revision() string {
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return ""
+	}
+	return snap.Revision
+}
+
+// LastRejection returns the most recent revision that failed to load,
+// or nil if the latest revision is the one being served
+func (r *Registry) LastRejection() *domain.SyncRejection {
+	return r.rejection.Load()
+}
+
+// Quarantined returns the index entries withheld from the served snapshot
+func (r *Registry) Quarantined() []domain.QuarantinedEntry {
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil
+	}
+	return snap.Quarantined
+}
+
+// ValidationReport returns the validation results for the served snapshot
+func (r *Registry) ValidationReport() *domain.ValidationReport {
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil
+	}
+	return snap.Validation
+}
+
+// ValidationSummary returns validation counts for health reporting
+func (r *Registry) ValidationSummary() *domain.ValidationSummary {
+	report := r.ValidationReport()
+	if report == nil {
+		return nil
+	}
+
+	summary := &domain.ValidationSummary{
+		Policy:  report.Policy,
+		Valid:   report.Valid,
+		Invalid: report.Invalid,
+	}
+	for _, s := range report.Servers {
+		if s.Excluded {
+			summary.Excluded++
+		}
+	}
+	return summary
+}
+
+// ServerStatus returns the lifecycle status of a deprecated or deleted
+// server, or nil if it is active
+func (r *Registry) ServerStatus(name string) *domain.ServerStatus {
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil
+	}
+	return snap.statuses[name]
+}
+
+// ValidationMeta returns the _meta flag for a server that is served despite
+// failing validation, or nil if it is valid
+func (r *Registry) ValidationMeta(name string) *domain.ValidationMeta {
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil
+	}
+	return validationMeta(snap.validation[name])
+}
+
+func validationMeta(v *domain.ServerValidation) *domain.ValidationMeta {
+	if v == nil || v.Valid {
+		return nil
+	}
+	return &domain.ValidationMeta{Valid: false, Issues: v.Issues}
+}
+
+// IndexMode returns how the index is built
+func (r *Registry) IndexMode() string {
+	return r.indexMode
+}
+
+// ScanDir returns the directory server files are found in, in scan and
+// compare modes
+func (r *Registry) ScanDir() string {
+	return r.scanDir
+}
+
+// IndexDrift returns the differences between index.yaml and the scanned
+// server files found in compare mode
+func (r *Registry) IndexDrift() []string {
+	snap := r.snapshot.Load()
+	if snap == nil {
+		return nil
+	}
+	return snap.Drift
+}
+
+// CacheMode returns how servers are held in memory
+func (r *Registry) CacheMode() string {
+	return r.cacheMode
+}
+
+// CacheStats returns current cache statistics. In preload mode size and
+// capacity are the number of preloaded servers; memory is approximate in
+// both modes.
 func (r *Registry) CacheStats() *domain.CacheStats {
 	hits := r.cacheHits.Load()
 	misses := r.cacheMisses.Load()
@@ -291,11 +669,25 @@ func (r *Registry) CacheStats() *domain.CacheStats {
 		hitRate = float64(hits) / float64(total)
 	}
 
-	return &domain.CacheStats{
-		Size:     r.cache.Len(),
-		Capacity: r.cacheSize,
-		HitRate:  hitRate,
+	stats := &domain.CacheStats{
+		Mode:         r.cacheMode,
+		HitRate:      hitRate,
+		Coalesced:    r.cacheCoalesced.Load(),
+		NegativeHits: r.negativeHits.Load(),
+	}
+	if r.cacheMode == CacheModePreload {
+		if snap := r.snapshot.Load(); snap != nil && snap.encoded != nil {
+			stats.Size = len(snap.encoded.servers)
+			stats.Capacity = stats.Size
+			stats.EstimatedMemoryBytes = snap.encoded.bytes
+		}
+		return stats
 	}
+
+	stats.Size = r.cache.Len()
+	stats.Capacity = r.cacheSize
+	stats.EstimatedMemoryBytes = r.cachedBytes.Load()
+	return stats
 }
 
 // LastSyncAt returns the last sync timestamp
@@ -303,7 +695,7 @@ func (r *Registry) LastSyncAt() time.Time {
 	return r.lastSyncAt.Load().(time.Time)
 }
 
-// Store returns the underlying git store
-func (r *Registry) Store() *gitstore.Store {
+// Source returns the underlying source backend
+func (r *Registry) Source() source.Source {
 	return r.store
 }
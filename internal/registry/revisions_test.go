@@ -0,0 +1,114 @@
+package registry
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+func TestLoadEarlierRevisions(t *testing.T) {
+	files := map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/legacy
+    path: servers/legacy.yaml
+  - name: com.example/mid
+    path: servers/mid.yaml
+  - name: com.example/unknown
+    path: servers/unknown.yaml
+`,
+		"servers/legacy.yaml": `$schema: ` + domain.SchemaURL("2025-07-09") + `
+name: com.example/legacy
+description: Written against the first revision
+version_detail:
+  version: 1.2.0
+website_url: https://example.com
+packages:
+  - registry_name: npm
+    name: "@example/legacy"
+    runtime_hint: npx
+    environment_variables:
+      - name: TOKEN
+        is_required: true
+remotes:
+  - transport_type: sse
+    url: https://example.com/sse
+`,
+		"servers/mid.yaml": `$schema: ` + domain.SchemaURL("2025-09-29") + `
+name: com.example/mid
+description: Written with a status string
+version: 1.0.0
+status: deprecated
+`,
+		"servers/unknown.yaml": `$schema: ` + domain.SchemaURL("2024-01-01") + `
+name: com.example/unknown
+description: Written against a revision nobody knows
+version: 1.0.0
+`,
+	}
+
+	for _, mode := range []string{CacheModePreload, CacheModeLazy} {
+		t.Run(mode, func(t *testing.T) {
+			r := newTestRegistry(t, Config{Store: localStore(t, files), CacheMode: mode})
+
+			// Served in the current revision
+			legacy, err := r.GetServer("com.example/legacy")
+			if err != nil {
+				t.Fatalf("GetServer(legacy): %v", err)
+			}
+			if legacy.Schema != domain.SchemaURL(domain.CurrentSchema) || legacy.Version != "1.2.0" ||
+				legacy.WebsiteURL != "https://example.com" {
+				t.Errorf("legacy = %+v, want it converted to the current revision", legacy)
+			}
+			if len(legacy.Packages) != 1 || legacy.Packages[0].RegistryType != "npm" ||
+				legacy.Packages[0].Identifier != "@example/legacy" || legacy.Packages[0].Transport.Type != "stdio" ||
+				len(legacy.Packages[0].EnvironmentVariables) != 1 || !legacy.Packages[0].EnvironmentVariables[0].IsRequired {
+				t.Errorf("legacy packages = %+v", legacy.Packages)
+			}
+			if len(legacy.Remotes) != 1 || legacy.Remotes[0].Type != "sse" {
+				t.Errorf("legacy remotes = %+v", legacy.Remotes)
+			}
+
+			mid, err := r.GetServer("com.example/mid")
+			if err != nil {
+				t.Fatalf("GetServer(mid): %v", err)
+			}
+			if mid.Schema != domain.SchemaURL(domain.CurrentSchema) {
+				t.Errorf("mid $schema = %s, want the current revision", mid.Schema)
+			}
+			if status := r.ServerStatus("com.example/mid"); status == nil || status.State != domain.StatusDeprecated {
+				t.Errorf("mid status = %+v, want the status string read as deprecated", status)
+			}
+
+			// Neither revision's schema is embedded, so both are validated
+			// after conversion and marked with the revision they declared
+			converted := map[string]string{
+				"com.example/legacy": domain.SchemaURL("2025-07-09"),
+				"com.example/mid":    domain.SchemaURL("2025-09-29"),
+			}
+			report := r.ValidationReport()
+			if len(report.Servers) != len(converted) {
+				t.Fatalf("validated %d servers, want %d", len(report.Servers), len(converted))
+			}
+			for _, v := range report.Servers {
+				if v.ConvertedFrom != converted[v.Name] || !v.Valid {
+					t.Errorf("%s validation = %+v, want valid and converted from %s", v.Name, v, converted[v.Name])
+				}
+			}
+			encoded, err := json.Marshal(report.Servers[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(encoded), `"converted_from":`) {
+				t.Errorf("validation JSON %s has no converted_from", encoded)
+			}
+
+			quarantined := r.Quarantined()
+			if len(quarantined) != 1 || quarantined[0].Name != "com.example/unknown" ||
+				!strings.Contains(quarantined[0].Reason, "unknown $schema") {
+				t.Errorf("quarantined = %+v, want only com.example/unknown for its $schema", quarantined)
+			}
+		})
+	}
+}
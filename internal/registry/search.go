@@ -0,0 +1,149 @@
+package registry
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Field weights: a term in the name counts as much as three in a description
+const (
+	weightName  = 3
+	weightTitle = 2
+	weightOther = 1
+)
+
+// searchIndex is an inverted index over the servers of a snapshot, keyed
+// by position in Index.Servers
+type searchIndex struct {
+	postings  map[string][]posting
+	terms     []string  // sorted vocabulary, for prefix matching
+	lengths   []float64 // weighted term count per server
+	avgLength float64
+}
+
+// posting records how often a term occurs in one server, weighted by field
+type posting struct {
+	doc  int
+	freq float64
+}
+
+// buildSearchIndex indexes the weighed terms of every server, by name; see
+// searchTerms
+func buildSearchIndex(entries []domain.IndexEntry, terms map[string]map[string]float64) *searchIndex {
+	idx := &searchIndex{
+		postings: make(map[string][]posting),
+		lengths:  make([]float64, len(entries)),
+	}
+
+	var total float64
+	for doc, entry := range entries {
+		for term, freq := range terms[entry.Name] {
+			idx.postings[term] = append(idx.postings[term], posting{doc: doc, freq: freq})
+			idx.lengths[doc] += freq
+		}
+		total += idx.lengths[doc]
+	}
+
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+
+	if len(entries) > 0 {
+		idx.avgLength = total / float64(len(entries))
+	}
+	return idx
+}
+
+// searchTerms weighs the terms of name, title, description, package
+// identifiers, environment variable names and remote URLs of a server
+func searchTerms(server *domain.ServerJSON) map[string]float64 {
+	freqs := make(map[string]float64)
+	add := func(text string, weight float64) {
+		for _, term := range tokenize(text) {
+			freqs[term] += weight
+		}
+	}
+
+	add(server.Name, weightName)
+	add(server.Title, weightTitle)
+	add(server.Description, weightOther)
+	for _, pkg := range server.Packages {
+		add(pkg.Identifier, weightOther)
+		for _, env := range pkg.EnvironmentVariables {
+			add(env.Name, weightOther)
+		}
+	}
+	for _, remote := range server.Remotes {
+		add(remote.URL, weightOther)
+	}
+	return freqs
+}
+
+// search returns the positions of servers matching any query term, best
+// match first. A query term also matches longer terms it is a prefix of,
+// so partially typed words still find results.
+func (idx *searchIndex) search(query string) []int {
+	scores := make(map[int]float64)
+	n := float64(len(idx.lengths))
+
+	seen := make(map[string]bool)
+	for _, qt := range tokenize(query) {
+		for _, term := range idx.expand(qt) {
+			if seen[term] {
+				continue
+			}
+			seen[term] = true
+
+			postings := idx.postings[term]
+			df := float64(len(postings))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			for _, p := range postings {
+				norm := 1 - bm25B + bm25B*idx.lengths[p.doc]/idx.avgLength
+				scores[p.doc] += idf * p.freq * (bm25K1 + 1) / (p.freq + bm25K1*norm)
+			}
+		}
+	}
+
+	results := make([]int, 0, len(scores))
+	for doc := range scores {
+		results = append(results, doc)
+	}
+	// Positions follow name order, so ties are broken by name
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		return a < b
+	})
+	return results
+}
+
+// expand returns the indexed terms starting with prefix
+func (idx *searchIndex) expand(prefix string) []string {
+	start := sort.SearchStrings(idx.terms, prefix)
+	end := start
+	for end < len(idx.terms) && strings.HasPrefix(idx.terms[end], prefix) {
+		end++
+	}
+	return idx.terms[start:end]
+}
+
+// tokenize lowercases text and splits it into letter and digit runs
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
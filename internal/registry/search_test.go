@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"slices"
+	"testing"
+)
+
+// catalogDefinitions are servers with packages and remotes, for search
+// and filters
+var catalogDefinitions = map[string]string{
+	"index.yaml": `servers:
+  - name: com.example/weather
+    path: servers/weather.yaml
+  - name: com.example/maps
+    path: servers/maps.yaml
+  - name: com.example/notes
+    path: servers/notes.yaml
+  - name: com.example/tools
+    path: servers/tools.yaml
+`,
+	"servers/weather.yaml": serverYAML("com.example/weather", "1.0.0", "Forecasts for any city") + `packages:
+  - registryType: npm
+    identifier: "@example/weather-mcp"
+    runtimeHint: npx
+    transport:
+      type: stdio
+    environmentVariables:
+      - name: WEATHER_API_KEY
+`,
+	"servers/maps.yaml": serverYAML("com.example/maps", "1.0.0", "Maps and routes with weather overlays") + `remotes:
+  - type: streamable-http
+    url: https://maps.example.com/mcp
+`,
+	"servers/notes.yaml": serverYAML("com.example/notes", "1.0.0", "Take and search notes") + `title: Notebook
+packages:
+  - registryType: pypi
+    identifier: notes-mcp
+    runtimeHint: uvx
+    transport:
+      type: stdio
+  - registryType: npm
+    identifier: "@example/notes"
+    runtimeHint: npx
+    transport:
+      type: streamable-http
+      url: http://localhost:3000/mcp
+remotes:
+  - type: sse
+    url: https://notes.example.com/sse
+`,
+	"servers/tools.yaml": serverYAML("com.example/tools", "1.0.0", "Assorted developer tools") + `packages:
+  - registryType: oci
+    identifier: example/tools
+    runtimeHint: docker
+    transport:
+      type: stdio
+    environmentVariables:
+      - name: GITHUB_TOKEN
+`,
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// A term in the name outranks the same term in a description
+		{query: "weather", want: []string{"com.example/weather", "com.example/maps"}},
+		{query: "WEATH", want: []string{"com.example/weather", "com.example/maps"}},
+		{query: "notebook", want: []string{"com.example/notes"}},
+		{query: "overlays", want: []string{"com.example/maps"}},
+		{query: "github_token", want: []string{"com.example/tools"}},
+		{query: "weather_api_key", want: []string{"com.example/weather", "com.example/maps"}},
+		{query: "sse", want: []string{"com.example/notes"}},
+		// Any term matches; servers matching more of them rank higher
+		{query: "weather forecasts", want: []string{"com.example/weather", "com.example/maps"}},
+		{query: "kubernetes", want: nil},
+	}
+
+	for _, mode := range []string{CacheModePreload, CacheModeLazy} {
+		r := newTestRegistry(t, Config{Store: localStore(t, catalogDefinitions), CacheMode: mode})
+
+		for _, tt := range tests {
+			t.Run(mode+"/"+tt.query, func(t *testing.T) {
+				var got []string
+				opts := ListOptions{Search: tt.query, Limit: 1}
+				for {
+					page, err := r.ListServers(opts)
+					if err != nil {
+						t.Fatalf("ListServers: %v", err)
+					}
+					for _, s := range page.Servers {
+						got = append(got, s.Server.Name)
+					}
+					if page.Metadata.NextCursor == "" || len(got) > 10 {
+						break
+					}
+					opts.Cursor = page.Metadata.NextCursor
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("search %q = %v, want %v", tt.query, got, tt.want)
+				}
+			})
+		}
+	}
+}
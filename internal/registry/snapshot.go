@@ -0,0 +1,487 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mcpregistry/server/internal/domain"
+	"github.com/mcpregistry/server/internal/schema"
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// Snapshot is an immutable, fully parsed view of the registry at one revision.
+// It is built off to the side during a sync and swapped in atomically.
+// Index only lists entries that passed integrity checks, sorted by name;
+// the rest are recorded in Quarantined and never served.
+type Snapshot struct {
+	Revision    string
+	Index       *domain.Index
+	Servers     map[string]*domain.ServerJSON // nil in lazy cache mode
+	Quarantined []domain.QuarantinedEntry
+	Drift       []string // differences from index.yaml in compare mode
+	Validation  *domain.ValidationReport
+	LoadedAt    time.Time
+
+	// validation results by server name
+	validation map[string]*domain.ServerValidation
+
+	// position of each server in Index.Servers
+	positions map[string]int
+
+	// pre-serialized responses, preload cache mode only
+	encoded *encodedSnapshot
+
+	// full-text index over the servers
+	search *searchIndex
+
+	// filterable attributes by position in Index.Servers, and their counts
+	facets      []serverFacets
+	facetCounts *domain.FacetsResponse
+
+	// creation and last change of each server by name
+	changes map[string]serverChange
+
+	// lifecycle of deprecated and deleted servers by name, and the
+	// positions of servers that are not deleted (nil if none are)
+	statuses map[string]*domain.ServerStatus
+	live     []int
+
+	// since of the statuses above that were dated from history
+	datedStatus map[string]time.Time
+
+	// changes from the snapshot this one replaced, nil for the first
+	diff *domain.SyncDiff
+}
+
+// entry returns a server's index entry
+func (s *Snapshot) entry(name string) (*domain.IndexEntry, bool) {
+	i, ok := s.positions[name]
+	if !ok {
+		return nil, false
+	}
+	return &s.Index.Servers[i], true
+}
+
+// selection is an ordered subset of Index.Servers: by name, or by
+// relevance for searches
+type selection struct {
+	positions []int  // nil selects every server
+	query     string // fingerprint of the query, carried in cursors
+	listed    bool   // the default listing, which preloaded pages cover
+}
+
+// listed selects every server that is not deleted
+func (s *Snapshot) listed() selection {
+	return selection{positions: s.live, query: listedQuery, listed: true}
+}
+
+// selection resolves the servers a list request asks for
+func (s *Snapshot) selection(opts ListOptions) selection {
+	if opts.Search == "" && opts.Filter.empty() && len(opts.Labels) == 0 && opts.UpdatedSince.IsZero() {
+		if opts.IncludeDeleted {
+			return selection{query: opts.fingerprint()}
+		}
+		return s.listed()
+	}
+
+	sel := selection{query: opts.fingerprint()}
+	if opts.Search != "" {
+		sel.positions = s.search.search(opts.Search)
+	}
+
+	matched := make([]int, 0, sel.len(s))
+	for i := range sel.len(s) {
+		p := sel.at(i)
+		entry := &s.Index.Servers[p]
+		if !opts.IncludeDeleted && s.deleted(entry.Name) {
+			continue
+		}
+		if !opts.UpdatedSince.IsZero() && !s.changes[entry.Name].updated.After(opts.UpdatedSince) {
+			continue
+		}
+		if opts.Filter.matches(s.facets[p]) && opts.Labels.Matches(entry.Labels) {
+			matched = append(matched, p)
+		}
+	}
+	sel.positions = matched
+	return sel
+}
+
+// deleted reports whether a server is a tombstone
+func (s *Snapshot) deleted(name string) bool {
+	st := s.statuses[name]
+	return st != nil && st.State == domain.StatusDeleted
+}
+
+func (sel selection) len(s *Snapshot) int {
+	if sel.positions == nil {
+		return len(s.Index.Servers)
+	}
+	return len(sel.positions)
+}
+
+// at returns the Index.Servers position of the i-th selected server
+func (sel selection) at(i int) int {
+	if sel.positions == nil {
+		return i
+	}
+	return sel.positions[i]
+}
+
+// SnapshotError reports why a revision could not be turned into a snapshot
+type SnapshotError struct {
+	Revision string
+	Errors   []string
+}
+
+func (e *SnapshotError) Error() string {
+	return fmt.Sprintf("revision %s rejected: %s", e.Revision, strings.Join(e.Errors, "; "))
+}
+
+// buildSnapshot resolves the index and reads every server it references at
+// the source's current revision. A missing or malformed index, or duplicate
+// entries, reject the whole revision. Entries whose file is missing, escapes
+// the repository, fails to parse, declares a different name or names an
+// unknown $schema are quarantined. Every loaded server is validated; under the strict policy
+// invalid servers are excluded, under the lenient policy they are flagged.
+func (r *Registry) buildSnapshot() (*Snapshot, error) {
+	revision := r.store.CurrentRevision()
+
+	built, err := r.buildIndex(revision)
+	if err != nil {
+		return nil, err
+	}
+	index := built.index
+
+	var errs []string
+	seen := make(map[string]bool, len(index.Servers))
+	for _, entry := range index.Servers {
+		if seen[entry.Name] {
+			errs = append(errs, fmt.Sprintf("%s: duplicate index entry", entry.Name))
+		}
+		seen[entry.Name] = true
+	}
+	if len(errs) > 0 {
+		return nil, &SnapshotError{Revision: revision, Errors: errs}
+	}
+
+	now := time.Now()
+	quarantined := built.quarantined
+	accepted := make([]domain.IndexEntry, 0, len(index.Servers))
+	servers := make(map[string]*domain.ServerJSON, len(index.Servers))
+	terms := make(map[string]map[string]float64, len(index.Servers))
+	facetsByName := make(map[string]serverFacets, len(index.Servers))
+	var definitionBytes int64
+	digests := make(map[string][sha256.Size]byte, len(index.Servers))
+	statuses := make(map[string]*domain.ServerStatus)
+	report := &domain.ValidationReport{
+		Revision:  revision,
+		Policy:    r.validationPolicy,
+		Schemas:   r.schemas.Known(),
+		CheckedAt: now,
+
+		Provisional: r.schemas.Provisional(),
+		Servers:     make([]domain.ServerValidation, 0, len(index.Servers)),
+	}
+	for _, entry := range index.Servers {
+		loaded, err := r.loadEntry(entry, r.store.ReadFile)
+		if err != nil {
+			quarantined = append(quarantined, domain.QuarantinedEntry{
+				Name:   entry.Name,
+				Path:   entry.Path,
+				Reason: err.Error(),
+			})
+			continue
+		}
+
+		result := domain.ServerValidation{
+			Name:   entry.Name,
+			Path:   entry.Path,
+			Issues: mergeIssues(loaded.issues, domain.ValidationIssues(loaded.server)),
+
+			ConvertedFrom: loaded.from,
+		}
+		result.Valid = !slices.ContainsFunc(result.Issues, blocking)
+		result.Excluded = !result.Valid && r.validationPolicy == ValidationStrict
+		report.Servers = append(report.Servers, result)
+		if result.Valid {
+			report.Valid++
+		} else {
+			report.Invalid++
+		}
+		if result.Excluded {
+			continue
+		}
+
+		// Lazy mode keeps only what search and filters need, so parsed
+		// servers do not pile up while a large catalog is read
+		accepted = append(accepted, entry)
+		terms[entry.Name] = searchTerms(loaded.server)
+		facetsByName[entry.Name] = extractFacets(loaded.server)
+		if r.cacheMode == CacheModePreload {
+			servers[entry.Name] = loaded.server
+			definitionBytes += loaded.size
+		}
+		digests[entry.Name] = loaded.digest
+		if loaded.status != nil {
+			statuses[entry.Name] = loaded.status
+		}
+	}
+	sort.Slice(accepted, func(i, j int) bool {
+		return accepted[i].Name < accepted[j].Name
+	})
+	index.Servers = accepted
+	report.Total = len(report.Servers)
+
+	changes := r.trackChanges(revision, accepted, digests, now)
+
+	dater := &statusDater{r: r, revision: revision, prev: r.snapshot.Load(), now: now}
+	datedStatus := make(map[string]time.Time)
+	positions := make(map[string]int, len(accepted))
+	facets := make([]serverFacets, len(accepted))
+	var live []int
+	liveFacets := make([]serverFacets, 0, len(accepted))
+	for i, entry := range accepted {
+		positions[entry.Name] = i
+		facets[i] = facetsByName[entry.Name]
+
+		st := statuses[entry.Name]
+		if st != nil && st.Since == nil {
+			since := dater.since(entry, st.State)
+			st.Since = &since
+			datedStatus[entry.Name] = since
+		}
+		if st != nil && st.State == domain.StatusDeleted {
+			if live == nil {
+				live = make([]int, 0, len(accepted))
+				for j := range i {
+					live = append(live, j)
+				}
+			}
+			continue
+		}
+		if live != nil {
+			live = append(live, i)
+		}
+		liveFacets = append(liveFacets, facets[i])
+	}
+
+	byName := make(map[string]*domain.ServerValidation, len(report.Servers))
+	for i := range report.Servers {
+		byName[report.Servers[i].Name] = &report.Servers[i]
+	}
+
+	snap := &Snapshot{
+		Revision:    revision,
+		Index:       index,
+		Servers:     servers,
+		Quarantined: quarantined,
+		Drift:       built.drift,
+		Validation:  report,
+		LoadedAt:    now,
+		validation:  byName,
+		positions:   positions,
+		search:      buildSearchIndex(accepted, terms),
+		facets:      facets,
+		facetCounts: countFacets(revision, liveFacets),
+		changes:     changes,
+		statuses:    statuses,
+		datedStatus: datedStatus,
+		live:        live,
+	}
+
+	prev := r.snapshot.Load()
+	if prev != nil {
+		snap.diff = r.diffSnapshots(prev, snap)
+	}
+
+	// Every server is parsed above for validation; lazy mode dropped them
+	// and reloads on demand
+	if r.cacheMode == CacheModePreload {
+		snap.encoded, err = encodeSnapshot(snap, prev)
+		if err != nil {
+			return nil, err
+		}
+		snap.encoded.bytes += definitionBytes
+	} else {
+		snap.Servers = nil
+	}
+
+	return snap, nil
+}
+
+// loadedEntry is a server definition read for a snapshot
+type loadedEntry struct {
+	server *domain.ServerJSON
+	issues []domain.ValidationIssue // found by the JSON Schema
+	digest [sha256.Size]byte        // of the definition file
+	status *domain.ServerStatus     // nil if active
+	from   string                   // $schema of a converted definition
+	size   int64                    // of the definition file
+}
+
+// loadEntry reads and checks the server definition an index entry points at,
+// along with any issues found by the JSON Schema its $schema names.
+// Definitions of an earlier revision are validated as written when its
+// schema is embedded, and as converted against the current schema
+// otherwise. Those naming any other schema that is not embedded are
+// rejected.
+func (r *Registry) loadEntry(entry domain.IndexEntry, read func(path string) ([]byte, error)) (*loadedEntry, error) {
+	path, err := source.CleanPath(entry.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", entry.Path, err)
+	}
+
+	parsed, err := parseServer(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", entry.Path, err)
+	}
+
+	if parsed.server.Name != entry.Name {
+		return nil, fmt.Errorf("%s declares name %q, index says %q", entry.Path, parsed.server.Name, entry.Name)
+	}
+
+	issues, err := r.schemas.Validate(parsed.doc)
+	if errors.Is(err, schema.ErrUnknownSchema) && parsed.upgraded != nil {
+		issues, err = r.schemas.Validate(parsed.upgraded)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", entry.Path, err)
+	}
+
+	status, issue, err := resolveStatus(entry, parsed.content)
+	if err != nil {
+		return nil, err
+	}
+	if issue != nil {
+		issues = append(issues, *issue)
+	}
+
+	return &loadedEntry{
+		server: parsed.server,
+		issues: issues,
+		digest: sha256.Sum256(content),
+		status: status,
+		from:   parsed.from,
+		size:   int64(len(content)),
+	}, nil
+}
+
+// parsedServer is a decoded server definition. doc is the document as
+// written, for validation against the schema it declares. Definitions of an
+// earlier schema revision are converted to the current one; upgraded and
+// content then hold the converted document and from the $schema declared.
+type parsedServer struct {
+	server   *domain.ServerJSON
+	doc      map[string]any
+	upgraded map[string]any
+	content  []byte
+	from     string
+}
+
+// parseServer decodes a server definition of any known schema revision
+func parseServer(content []byte) (*parsedServer, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	parsed := &parsedServer{doc: doc, content: content}
+
+	// Convert a second decoding, leaving doc as written
+	var upgraded map[string]any
+	if err := yaml.Unmarshal(content, &upgraded); err != nil {
+		return nil, err
+	}
+	if declared, _ := doc["$schema"].(string); domain.UpgradeServer(upgraded) {
+		converted, err := yaml.Marshal(upgraded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert from %s: %w", declared, err)
+		}
+		parsed.upgraded = upgraded
+		parsed.content = converted
+		parsed.from = declared
+	}
+
+	var server domain.ServerJSON
+	if err := yaml.Unmarshal(parsed.content, &server); err != nil {
+		return nil, err
+	}
+	parsed.server = &server
+	return parsed, nil
+}
+
+// resolveStatus returns the lifecycle status of a server, taken from its
+// index entry or else from the status block of its definition. A block
+// without a state is reported as an issue and the server treated as active.
+func resolveStatus(entry domain.IndexEntry, content []byte) (*domain.ServerStatus, *domain.ValidationIssue, error) {
+	status := entry.Status
+	field := "status.state"
+	if status != nil {
+		field = indexFile + " " + field
+	} else {
+		var def struct {
+			Status *domain.ServerStatus `yaml:"status"`
+		}
+		if err := yaml.Unmarshal(content, &def); err != nil {
+			return nil, nil, fmt.Errorf("%s: invalid status block: %w", entry.Path, err)
+		}
+		status = def.Status
+	}
+	if status == nil {
+		return nil, nil, nil
+	}
+
+	switch status.State {
+	case domain.StatusActive:
+		return nil, nil, nil
+	case domain.StatusDeprecated, domain.StatusDeleted:
+		copied := *status
+		return &copied, nil, nil
+	case "":
+		return nil, &domain.ValidationIssue{
+			Field:   "status.state",
+			Rule:    "required",
+			Message: field + " is required",
+		}, nil
+	default:
+		return nil, nil, fmt.Errorf("%s: unknown status %q, must be %q, %q or %q", entry.Path, status.State,
+			domain.StatusActive, domain.StatusDeprecated, domain.StatusDeleted)
+	}
+}
+
+// mergeIssues combines JSON Schema issues with struct tag issues. The
+// schema is authoritative, so struct issues for a field it already
+// reported are dropped, unless the schema's issue is only advisory.
+func mergeIssues(schemaIssues, structIssues []domain.ValidationIssue) []domain.ValidationIssue {
+	reported := make(map[string]bool, len(schemaIssues))
+	for _, issue := range schemaIssues {
+		if blocking(issue) {
+			reported[issue.Field] = true
+		}
+	}
+
+	issues := schemaIssues
+	for _, issue := range structIssues {
+		if !reported[issue.Field] {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// blocking reports whether an issue makes a definition invalid
+func blocking(issue domain.ValidationIssue) bool {
+	return !issue.Advisory
+}
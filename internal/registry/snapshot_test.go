@@ -0,0 +1,122 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+func TestResolveStatus(t *testing.T) {
+	const path = "servers/example.yaml"
+
+	tests := []struct {
+		name      string
+		index     *domain.ServerStatus
+		content   string
+		want      *domain.ServerStatus
+		wantIssue *domain.ValidationIssue
+		wantErr   bool
+	}{
+		{
+			name:    "no status",
+			content: "name: io.example/server\n",
+		},
+		{
+			name:    "active in file",
+			content: "status:\n  state: active\n",
+		},
+		{
+			name:    "deprecated in file",
+			content: "status:\n  state: deprecated\n  message: use v2\n  replacement: io.example/v2\n",
+			want: &domain.ServerStatus{
+				State:       domain.StatusDeprecated,
+				Message:     "use v2",
+				Replacement: "io.example/v2",
+			},
+		},
+		{
+			name:    "deleted in file",
+			content: "status:\n  state: deleted\n",
+			want:    &domain.ServerStatus{State: domain.StatusDeleted},
+		},
+		{
+			name:    "index overrides file",
+			index:   &domain.ServerStatus{State: domain.StatusDeprecated},
+			content: "status:\n  state: deleted\n",
+			want:    &domain.ServerStatus{State: domain.StatusDeprecated},
+		},
+		{
+			name:    "index ignores unparsable file",
+			index:   &domain.ServerStatus{State: domain.StatusActive},
+			content: "status: [",
+		},
+		{
+			name:    "empty state in file",
+			content: "status:\n  message: gone\n",
+			wantIssue: &domain.ValidationIssue{
+				Field:   "status.state",
+				Rule:    "required",
+				Message: "status.state is required",
+			},
+		},
+		{
+			name:  "empty state in index",
+			index: &domain.ServerStatus{Message: "gone"},
+			wantIssue: &domain.ValidationIssue{
+				Field:   "status.state",
+				Rule:    "required",
+				Message: indexFile + " status.state is required",
+			},
+		},
+		{
+			name:    "unknown state",
+			content: "status:\n  state: retired\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown state in index",
+			index:   &domain.ServerStatus{State: "retired"},
+			wantErr: true,
+		},
+		{
+			name:    "status not an object",
+			content: "status: deprecated\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := domain.IndexEntry{Name: "io.example/server", Path: path, Status: tt.index}
+			got, issue, err := resolveStatus(entry, []byte(tt.content))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolveStatus() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveStatus() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveStatus() status = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(issue, tt.wantIssue) {
+				t.Errorf("resolveStatus() issue = %+v, want %+v", issue, tt.wantIssue)
+			}
+		})
+	}
+}
+
+func TestResolveStatusCopiesIndexStatus(t *testing.T) {
+	index := &domain.ServerStatus{State: domain.StatusDeprecated}
+	got, _, err := resolveStatus(domain.IndexEntry{Name: "io.example/server", Status: index}, nil)
+	if err != nil {
+		t.Fatalf("resolveStatus() error = %v", err)
+	}
+	got.Message = "changed"
+	if index.Message != "" {
+		t.Errorf("resolveStatus() returned the index entry's status, not a copy")
+	}
+}
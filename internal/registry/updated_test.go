@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestServerTimestamps(t *testing.T) {
+	repo := newGitFixture(t)
+	hour := func(n int) time.Time { return fixtureStart.Add(time.Duration(n) * time.Hour) }
+
+	first := repo.commit(map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/maps
+    path: servers/maps.yaml
+  - name: com.example/weather
+    path: servers/weather.yaml
+`,
+		"servers/maps.yaml":    serverYAML("com.example/maps", "1.0.0", "Maps"),
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.0.0", "Weather"),
+	})
+	changed := repo.commit(map[string]string{
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.1.0", "Weather"),
+	})
+	added := repo.commit(map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/maps
+    path: servers/maps.yaml
+  - name: com.example/notes
+    path: servers/notes.yaml
+  - name: com.example/weather
+    path: servers/weather.yaml
+`,
+		"servers/notes.yaml": serverYAML("com.example/notes", "1.0.0", "Notes"),
+	})
+	// Commits that leave a definition alone do not date it
+	repo.commit(map[string]string{"README.md": "# Catalog\n"})
+
+	want := map[string]struct {
+		created, updated time.Time
+		commit           string
+	}{
+		"com.example/maps":    {created: hour(0), updated: hour(0), commit: first},
+		"com.example/weather": {created: hour(0), updated: hour(1), commit: changed},
+		"com.example/notes":   {created: hour(2), updated: hour(2), commit: added},
+	}
+
+	for _, mode := range []string{CacheModePreload, CacheModeLazy} {
+		r := newTestRegistry(t, Config{Store: repo.store(), CacheMode: mode})
+
+		t.Run(mode+"/meta", func(t *testing.T) {
+			list, err := r.ListServers(ListOptions{})
+			if err != nil {
+				t.Fatalf("ListServers: %v", err)
+			}
+			for _, s := range list.Servers {
+				name, w := s.Server.Name, want[s.Server.Name]
+				official := s.Meta.Official
+				if !official.PublishedAt.Equal(w.created) || !official.UpdatedAt.Equal(w.updated) || official.Commit != w.commit {
+					t.Errorf("%s dated %v / %v at %s, want %v / %v at %s", name,
+						official.PublishedAt, official.UpdatedAt, official.Commit, w.created, w.updated, w.commit)
+				}
+			}
+		})
+
+		tests := []struct {
+			since time.Time
+			want  []string
+		}{
+			{since: hour(0).Add(-time.Second), want: []string{"com.example/maps", "com.example/notes", "com.example/weather"}},
+			// Only changes after the given time count
+			{since: hour(0), want: []string{"com.example/notes", "com.example/weather"}},
+			{since: hour(1), want: []string{"com.example/notes"}},
+			{since: hour(2)},
+		}
+		for _, tt := range tests {
+			t.Run(mode+"/since "+tt.since.Format(time.RFC3339), func(t *testing.T) {
+				list, err := r.ListServers(ListOptions{UpdatedSince: tt.since})
+				if err != nil {
+					t.Fatalf("ListServers: %v", err)
+				}
+				var got []string
+				for _, s := range list.Servers {
+					got = append(got, s.Server.Name)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("updated since %v: %v, want %v", tt.since, got, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package registry
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// validationFiles has one valid server, one that loads but fails validation
+// and several entries that cannot be loaded at all
+var validationFiles = map[string]string{
+	"index.yaml": `servers:
+  - name: com.example/valid
+    path: servers/valid.yaml
+  - name: com.example/invalid
+    path: servers/invalid.yaml
+  - name: com.example/missing
+    path: servers/missing.yaml
+  - name: com.example/renamed
+    path: servers/renamed.yaml
+  - name: com.example/garbled
+    path: servers/garbled.yaml
+`,
+	"servers/valid.yaml":   serverYAML("com.example/valid", "1.0.0", "A valid server"),
+	"servers/invalid.yaml": serverYAML("com.example/invalid", "latest", "Not a semantic version"),
+	"servers/renamed.yaml": serverYAML("com.example/other", "1.0.0", "Declares another name"),
+	"servers/garbled.yaml": "name: [unterminated\n",
+}
+
+func TestValidationPolicy(t *testing.T) {
+	tests := []struct {
+		policy       string
+		wantListed   []string
+		wantExcluded int
+	}{
+		{
+			policy:     ValidationLenient,
+			wantListed: []string{"com.example/invalid", "com.example/valid"},
+		},
+		{
+			policy:       ValidationStrict,
+			wantListed:   []string{"com.example/valid"},
+			wantExcluded: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			r := newTestRegistry(t, Config{Store: localStore(t, validationFiles), ValidationPolicy: tt.policy})
+
+			list, err := r.ListServers(ListOptions{})
+			if err != nil {
+				t.Fatalf("ListServers: %v", err)
+			}
+			var listed []string
+			for _, s := range list.Servers {
+				listed = append(listed, s.Server.Name)
+			}
+			if !slices.Equal(listed, tt.wantListed) {
+				t.Errorf("listed %v, want %v", listed, tt.wantListed)
+			}
+
+			summary := r.ValidationSummary()
+			want := domain.ValidationSummary{Policy: tt.policy, Valid: 1, Invalid: 1, Excluded: tt.wantExcluded}
+			if summary == nil || *summary != want {
+				t.Errorf("ValidationSummary() = %+v, want %+v", summary, want)
+			}
+
+			meta := r.ValidationMeta("com.example/invalid")
+			if meta == nil || meta.Valid || !hasIssue(meta.Issues, "version") {
+				t.Errorf("ValidationMeta(invalid) = %+v, want a version issue", meta)
+			}
+			// Valid servers carry no validation _meta
+			if meta := r.ValidationMeta("com.example/valid"); meta != nil {
+				t.Errorf("ValidationMeta(valid) = %+v, want none", meta)
+			}
+
+			_, err = r.GetServer("com.example/invalid")
+			if excluded := tt.wantExcluded > 0; excluded != (err != nil) {
+				t.Errorf("GetServer(invalid) error = %v under the %s policy", err, tt.policy)
+			}
+		})
+	}
+}
+
+func hasIssue(issues []domain.ValidationIssue, field string) bool {
+	for _, issue := range issues {
+		if issue.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestQuarantine(t *testing.T) {
+	r := newTestRegistry(t, Config{Store: localStore(t, validationFiles)})
+
+	reasons := make(map[string]string)
+	for _, q := range r.Quarantined() {
+		reasons[q.Name] = q.Reason
+	}
+	want := map[string]string{
+		"com.example/missing": "failed to read servers/missing.yaml",
+		"com.example/renamed": `declares name "com.example/other"`,
+		"com.example/garbled": "failed to parse servers/garbled.yaml",
+	}
+	if len(reasons) != len(want) {
+		t.Errorf("quarantined %v, want %d entries", reasons, len(want))
+	}
+	for name, reason := range want {
+		if !strings.Contains(reasons[name], reason) {
+			t.Errorf("%s quarantined for %q, want it to mention %q", name, reasons[name], reason)
+		}
+		if _, err := r.GetServer(name); err == nil {
+			t.Errorf("GetServer(%s) served a quarantined entry", name)
+		}
+	}
+
+	// Quarantined entries are not validated, so they are not in the report
+	if report := r.ValidationReport(); report.Total != 2 {
+		t.Errorf("validation report covers %d servers, want 2", report.Total)
+	}
+}
+
+func TestDuplicateEntriesRejectRevision(t *testing.T) {
+	files := map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/valid
+    path: servers/valid.yaml
+  - name: com.example/valid
+    path: servers/valid.yaml
+`,
+		"servers/valid.yaml": serverYAML("com.example/valid", "1.0.0", "A valid server"),
+	}
+	r, err := New(Config{Store: localStore(t, files), Logger: discardLogger()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.LoadIndex()
+	var snapErr *SnapshotError
+	if !errors.As(err, &snapErr) {
+		t.Fatalf("LoadIndex() = %v, want a SnapshotError", err)
+	}
+	if rejection := r.LastRejection(); rejection == nil || len(rejection.Errors) != 1 {
+		t.Errorf("LastRejection() = %+v, want the duplicate reported", rejection)
+	}
+	if r.ServerCount() != 0 {
+		t.Errorf("served %d servers from a rejected revision", r.ServerCount())
+	}
+}
+
+func TestProvisionalSchemaIssuesAdvisory(t *testing.T) {
+	// The schema wants a string title; the service's own rules accept any
+	files := map[string]string{
+		"index.yaml": `servers:
+  - name: com.example/titled
+    path: servers/titled.yaml
+`,
+		"servers/titled.yaml": serverYAML("com.example/titled", "1.0.0", "Numeric title") + "title: 5\n",
+	}
+	r := newTestRegistry(t, Config{Store: localStore(t, files), ValidationPolicy: ValidationStrict})
+
+	report := r.ValidationReport()
+	if len(report.Provisional) == 0 {
+		t.Skip("every embedded schema is an upstream document")
+	}
+	if report.Valid != 1 || len(report.Servers) != 1 {
+		t.Fatalf("report = %+v, want the server valid", report)
+	}
+	issues := report.Servers[0].Issues
+	if !hasIssue(issues, "title") || !issues[0].Advisory {
+		t.Errorf("issues = %+v, want an advisory title issue", issues)
+	}
+	if _, err := r.GetServer("com.example/titled"); err != nil {
+		t.Errorf("GetServer excluded a server with only advisory issues: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const versionsIndex = `version: "1"
+servers:
+  - name: com.example/weather
+    path: servers/weather.yaml
+`
+
+func TestGetServerVersions(t *testing.T) {
+	repo := newGitFixture(t)
+	repo.commit(map[string]string{
+		"index.yaml":           versionsIndex,
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.0.0", "Weather forecasts"),
+	})
+	reworded := repo.commit(map[string]string{
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.0.0", "Weather forecasts and alerts"),
+	})
+	minor := repo.commit(map[string]string{
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.1.0", "Weather forecasts and alerts"),
+	})
+	repo.commit(map[string]string{"README.md": "unrelated\n"})
+	major := repo.commit(map[string]string{
+		"servers/weather.yaml": serverYAML("com.example/weather", "2.0.0", "Weather forecasts and alerts"),
+	})
+
+	r := newTestRegistry(t, Config{Store: repo.store()})
+
+	versions, err := r.GetServerVersions("com.example%2Fweather")
+	if err != nil {
+		t.Fatalf("GetServerVersions: %v", err)
+	}
+
+	want := []struct {
+		version   string
+		commit    string
+		published time.Time
+		latest    bool
+	}{
+		{"2.0.0", major, fixtureStart.Add(4 * time.Hour), true},
+		{"1.1.0", minor, fixtureStart.Add(2 * time.Hour), false},
+		// Published by the first commit, last carried by the reword
+		{"1.0.0", reworded, fixtureStart, false},
+	}
+	if len(versions) != len(want) {
+		t.Fatalf("got %d versions, want %d", len(versions), len(want))
+	}
+	for i, w := range want {
+		info := versions[i].Info
+		if info.Version != w.version || info.Commit != w.commit ||
+			!info.PublishedAt.Equal(w.published) || info.IsLatest != w.latest {
+			t.Errorf("versions[%d] = %+v, want %+v", i, info, w)
+		}
+		if versions[i].Server.Version != w.version {
+			t.Errorf("versions[%d] carries server version %s", i, versions[i].Server.Version)
+		}
+	}
+	if versions[2].Server.Description != "Weather forecasts and alerts" {
+		t.Errorf("1.0.0 definition = %q, want the one from its latest commit", versions[2].Server.Description)
+	}
+
+	latest, err := r.GetServerVersion("com.example/weather", "latest")
+	if err != nil || latest.Info.Version != "2.0.0" {
+		t.Errorf("GetServerVersion(latest) = %v, %v; want 2.0.0", latest, err)
+	}
+	if _, err := r.GetServerVersion("com.example/weather", "3.0.0"); err == nil {
+		t.Error("GetServerVersion of an unpublished version succeeded")
+	}
+	if _, err := r.GetServerVersions("com.example/unknown"); err == nil {
+		t.Error("GetServerVersions of an unknown server succeeded")
+	}
+}
+
+func TestGetServerVersionsAfterSync(t *testing.T) {
+	repo := newGitFixture(t)
+	repo.commit(map[string]string{
+		"index.yaml":           versionsIndex,
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.0.0", "Weather forecasts"),
+	})
+	store := repo.store()
+	r := newTestRegistry(t, Config{Store: store})
+
+	if versions, err := r.GetServerVersions("com.example/weather"); err != nil || len(versions) != 1 {
+		t.Fatalf("GetServerVersions = %d versions, %v; want 1", len(versions), err)
+	}
+
+	repo.commit(map[string]string{
+		"servers/weather.yaml": serverYAML("com.example/weather", "1.1.0", "Weather forecasts"),
+	})
+	if _, err := store.Pull(context.Background()); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if _, err := r.Refresh(); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// The history cached at the previous revision must not be served
+	versions, err := r.GetServerVersions("com.example/weather")
+	if err != nil {
+		t.Fatalf("GetServerVersions: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Info.Version != "1.1.0" || !versions[0].Info.IsLatest {
+		t.Errorf("versions after sync = %+v, want 1.1.0 then 1.0.0", versions)
+	}
+}
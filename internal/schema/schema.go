@@ -0,0 +1,225 @@
+package schema
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// schemaFS holds one official server.json schema per published date, as
+// schemas/<date>/server.schema.json. To support a new date, download
+// BaseURL/<date>/server.schema.json into a new directory with
+// scripts/fetch-schemas.sh.
+//
+//go:embed schemas/*/server.schema.json
+var schemaFS embed.FS
+
+// BaseURL is the prefix of every official server.json schema URL
+const BaseURL = domain.SchemaBaseURL
+
+// ErrUnknownSchema is returned for $schema URLs that are not embedded
+var ErrUnknownSchema = errors.New("unknown $schema")
+
+// provisional holds the dates whose embedded file was written by hand
+// rather than downloaded. Their findings are advisory, so a schema that may
+// differ from upstream never makes a definition invalid. Remove a date once
+// scripts/fetch-schemas.sh has replaced its file.
+var provisional = map[string]bool{
+	"2025-12-11": true,
+}
+
+var printer = message.NewPrinter(language.English)
+
+// Validator holds the compiled schemas keyed by URL
+type Validator struct {
+	schemas     map[string]*jsonschema.Schema
+	provisional map[string]bool
+}
+
+// New compiles every embedded schema
+func New() (*Validator, error) {
+	dirs, err := fs.ReadDir(schemaFS, "schemas")
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+
+	urls := make([]string, 0, len(dirs))
+	for _, d := range dirs {
+		content, err := schemaFS.ReadFile(path.Join("schemas", d.Name(), "server.schema.json"))
+		if err != nil {
+			return nil, err
+		}
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", d.Name(), err)
+		}
+
+		// Each file is registered under the URL of its directory, so a
+		// misplaced download must not stand in for another date
+		url := URL(d.Name())
+		if obj, ok := doc.(map[string]any); !ok || obj["$id"] != url {
+			return nil, fmt.Errorf("schema %s: $id does not match %s", d.Name(), url)
+		}
+		if err := compiler.AddResource(url, doc); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", d.Name(), err)
+		}
+		urls = append(urls, url)
+	}
+
+	v := &Validator{
+		schemas:     make(map[string]*jsonschema.Schema, len(urls)),
+		provisional: make(map[string]bool),
+	}
+	for _, url := range urls {
+		sch, err := compiler.Compile(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile %s: %w", url, err)
+		}
+		v.schemas[url] = sch
+		if provisional[domain.SchemaDate(url)] {
+			v.provisional[url] = true
+		}
+	}
+
+	return v, nil
+}
+
+// URL returns the official schema URL for a schema date
+func URL(date string) string {
+	return domain.SchemaURL(date)
+}
+
+// Known returns the supported schema URLs in ascending date order
+func (v *Validator) Known() []string {
+	urls := make([]string, 0, len(v.schemas))
+	for url := range v.schemas {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// Provisional returns the embedded schema URLs that are not upstream
+// documents, in ascending date order
+func (v *Validator) Provisional() []string {
+	urls := make([]string, 0, len(v.provisional))
+	for url := range v.provisional {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// Validate checks a decoded server definition against the schema its
+// $schema field names. It returns ErrUnknownSchema if that schema is not
+// embedded, and one issue per failed keyword otherwise. Issues found by a
+// provisional schema are marked advisory.
+func (v *Validator) Validate(doc map[string]any) ([]domain.ValidationIssue, error) {
+	url, _ := doc["$schema"].(string)
+	sch, ok := v.schemas[url]
+	if !ok {
+		if url == "" {
+			return nil, fmt.Errorf("%w: missing", ErrUnknownSchema)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, url)
+	}
+
+	instance, err := toJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sch.Validate(instance)
+	if err == nil {
+		return nil, nil
+	}
+
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return nil, err
+	}
+
+	var issues []domain.ValidationIssue
+	collectIssues(verr, &issues)
+
+	// Alternatives in anyOf branches often fail the same way
+	seen := make(map[string]bool, len(issues))
+	unique := issues[:0]
+	for _, issue := range issues {
+		if !seen[issue.Message] {
+			seen[issue.Message] = true
+			issue.Advisory = v.provisional[url]
+			unique = append(unique, issue)
+		}
+	}
+	return unique, nil
+}
+
+// collectIssues flattens the leaves of a validation error tree
+func collectIssues(err *jsonschema.ValidationError, issues *[]domain.ValidationIssue) {
+	if len(err.Causes) > 0 {
+		for _, cause := range err.Causes {
+			collectIssues(cause, issues)
+		}
+		return
+	}
+
+	var rule string
+	if kw := err.ErrorKind.KeywordPath(); len(kw) > 0 {
+		rule = kw[len(kw)-1]
+	}
+
+	field := fieldPath(err.InstanceLocation)
+	msg := err.ErrorKind.LocalizedString(printer)
+	if field != "" {
+		msg = field + ": " + msg
+	}
+
+	*issues = append(*issues, domain.ValidationIssue{
+		Field:   field,
+		Rule:    rule,
+		Message: msg,
+	})
+}
+
+// fieldPath renders an instance location as e.g. packages[0].transport.type
+func fieldPath(tokens []string) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		if _, err := strconv.Atoi(tok); err == nil {
+			sb.WriteString("[" + tok + "]")
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(tok)
+	}
+	return sb.String()
+}
+
+// toJSON round-trips a YAML-decoded document through encoding/json so
+// numbers, timestamps and maps have the types the validator expects
+func toJSON(doc map[string]any) (any, error) {
+	content, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(content))
+}
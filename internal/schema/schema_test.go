@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+func newValidator(t *testing.T) *Validator {
+	t.Helper()
+	v, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return v
+}
+
+func TestKnown(t *testing.T) {
+	v := newValidator(t)
+	if !slices.Contains(v.Known(), URL(domain.CurrentSchema)) {
+		t.Errorf("Known() = %v, want the current schema", v.Known())
+	}
+	for _, url := range v.Provisional() {
+		if !slices.Contains(v.Known(), url) {
+			t.Errorf("provisional schema %s is not embedded", url)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	current := URL(domain.CurrentSchema)
+	tests := []struct {
+		name       string
+		doc        map[string]any
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			doc: map[string]any{
+				"$schema":     current,
+				"name":        "com.example/server",
+				"description": "A server",
+				"version":     "1.0.0",
+			},
+		},
+		{
+			name: "missing description",
+			doc: map[string]any{
+				"$schema": current,
+				"name":    "com.example/server",
+				"version": "1.0.0",
+			},
+			wantFields: []string{""},
+		},
+		{
+			name: "wrong type",
+			doc: map[string]any{
+				"$schema":     current,
+				"name":        "com.example/server",
+				"description": "A server",
+				"version":     1,
+			},
+			wantFields: []string{"version"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newValidator(t)
+			// Findings of a provisional schema are advisory; without the
+			// flag the same findings are binding
+			for _, provisional := range []bool{true, false} {
+				v.provisional[current] = provisional
+
+				issues, err := v.Validate(tt.doc)
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				var fields []string
+				for _, issue := range issues {
+					fields = append(fields, issue.Field)
+					if issue.Advisory != provisional {
+						t.Errorf("issue %q advisory = %t, want %t", issue.Message, issue.Advisory, provisional)
+					}
+				}
+				if !slices.Equal(fields, tt.wantFields) {
+					t.Errorf("issues for fields %q, want %q (%+v)", fields, tt.wantFields, issues)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateUnknownSchema(t *testing.T) {
+	v := newValidator(t)
+	for _, schema := range []any{
+		nil,
+		"",
+		URL("2099-01-01"),
+		"https://example.com/server.schema.json",
+		42,
+	} {
+		doc := map[string]any{"name": "com.example/server", "description": "A server", "version": "1.0.0"}
+		if schema != nil {
+			doc["$schema"] = schema
+		}
+		if _, err := v.Validate(doc); !errors.Is(err, ErrUnknownSchema) {
+			t.Errorf("Validate($schema %v) = %v, want ErrUnknownSchema", schema, err)
+		}
+	}
+}
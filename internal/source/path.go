@@ -0,0 +1,39 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrUnsafePath is returned for paths that would resolve outside the
+// repository tree
+var ErrUnsafePath = errors.New("path escapes repository root")
+
+// CleanPath normalizes a slash-separated path relative to the repository
+// root. Absolute paths and paths that climb above the root are rejected.
+func CleanPath(p string) (string, error) {
+	if p == "" {
+		return "", fmt.Errorf("%w: empty path", ErrUnsafePath)
+	}
+	if strings.ContainsRune(p, '\\') || strings.ContainsRune(p, 0) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, p)
+	}
+	if path.IsAbs(p) {
+		return "", fmt.Errorf("%w: %q is absolute", ErrUnsafePath, p)
+	}
+
+	cleaned := path.Clean(p)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, p)
+	}
+	return cleaned, nil
+}
+
+// InDir reports whether a slash-separated path lies under dir. An empty
+// dir is the repository root.
+func InDir(p, dir string) bool {
+	dir = strings.Trim(dir, "/")
+	return dir == "" || strings.HasPrefix(p, dir+"/")
+}
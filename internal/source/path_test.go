@@ -0,0 +1,72 @@
+package source
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "servers/example.yaml", want: "servers/example.yaml"},
+		{input: "./servers/example.yaml", want: "servers/example.yaml"},
+		{input: "servers//example.yaml", want: "servers/example.yaml"},
+		{input: "servers/../other/example.yaml", want: "other/example.yaml"},
+		{input: "..servers/example.yaml", want: "..servers/example.yaml"},
+		{input: "", wantErr: true},
+		{input: "/etc/passwd", wantErr: true},
+		{input: "..", wantErr: true},
+		{input: "../example.yaml", wantErr: true},
+		{input: "servers/../../example.yaml", wantErr: true},
+		{input: `servers\..\..\example.yaml`, wantErr: true},
+		{input: "servers/example.yaml\x00.txt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := CleanPath(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsafePath) {
+					t.Fatalf("CleanPath(%q) = %q, %v, want ErrUnsafePath", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CleanPath(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("CleanPath(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInDir(t *testing.T) {
+	tests := []struct {
+		path string
+		dir  string
+		want bool
+	}{
+		{path: "servers/example.yaml", dir: "servers", want: true},
+		{path: "servers/example.yaml", dir: "servers/", want: true},
+		{path: "servers/example.yaml", dir: "/servers/", want: true},
+		{path: "servers/nested/example.yaml", dir: "servers", want: true},
+		{path: "servers/example.yaml", dir: "", want: true},
+		{path: "servers/example.yaml", dir: "/", want: true},
+		{path: "servers-old/example.yaml", dir: "servers", want: false},
+		{path: "serversexample.yaml", dir: "servers", want: false},
+		{path: "servers", dir: "servers", want: false},
+		{path: "other/servers/example.yaml", dir: "servers", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir+":"+tt.path, func(t *testing.T) {
+			if got := InDir(tt.path, tt.dir); got != tt.want {
+				t.Errorf("InDir(%q, %q) = %v, want %v", tt.path, tt.dir, got, tt.want)
+			}
+		})
+	}
+}
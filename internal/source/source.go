@@ -0,0 +1,126 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Source provides read access to a checkout of the registry data repository
+type Source interface {
+	// ReadFile reads a file relative to the repository root
+	ReadFile(path string) ([]byte, error)
+
+	// ListFiles returns the names of all files in a directory
+	ListFiles(dir string) ([]string, error)
+
+	// WalkFiles calls fn for every file under dir that match accepts.
+	// Rejected files are not read.
+	WalkFiles(dir string, match func(path string) bool, fn func(path string, content []byte) error) error
+
+	// CurrentRevision identifies the content currently being served
+	CurrentRevision() string
+
+	// Refresh picks up upstream changes and reports whether the revision changed
+	Refresh(ctx context.Context) (bool, error)
+
+	// Info describes where the source reads from
+	Info() Info
+}
+
+// Info describes a source backend and what it is currently serving
+type Info struct {
+	Type     string
+	Location string
+
+	// Mode is how the revision is chosen, e.g. "branch", "tag" or "commit",
+	// and Ref is the branch name, tag pattern or commit it applies to
+	Mode string
+	Ref  string
+
+	// Tag is the tag being served in tag mode
+	Tag string
+}
+
+// History is implemented by sources that retain per-file history
+type History interface {
+	// FileHistory returns the revisions of path reachable from revision, newest first
+	FileHistory(revision, path string) ([]FileRevision, error)
+}
+
+// ChangeTracker is implemented by sources that know when each file changed
+type ChangeTracker interface {
+	// FileChanges returns the change times of every file added or
+	// modified in the history of revision, keyed by path. The map must
+	// not be modified.
+	FileChanges(revision string) (map[string]FileChange, error)
+}
+
+// FileChange records when a file was created and last modified
+type FileChange struct {
+	Created time.Time
+	Updated time.Time
+	Commit  string // last commit that modified the file
+}
+
+// Differ is implemented by sources that can list the files changed
+// between two revisions
+type Differ interface {
+	// ChangedPaths returns the paths added, modified or deleted between
+	// from and to, sorted
+	ChangedPaths(from, to string) ([]string, error)
+}
+
+// ErrUnknownCommit is returned for commits that are not in the history of
+// the revision being served
+var ErrUnknownCommit = errors.New("unknown commit")
+
+// Timeline is implemented by sources that can locate earlier points in
+// the history of a revision
+type Timeline interface {
+	// ResolveCommit expands a full or abbreviated commit hash reachable
+	// from revision
+	ResolveCommit(revision, ref string) (string, error)
+	// CommitAt returns the newest first-parent ancestor of revision
+	// committed at or before t, or "" if the history starts later
+	CommitAt(revision string, t time.Time) (string, error)
+}
+
+// RevisionReader is implemented by sources that can read a file as of an
+// earlier revision, so lazily loaded content matches the served snapshot
+type RevisionReader interface {
+	ReadFileAt(revision, path string) ([]byte, error)
+}
+
+// StaleReporter is implemented by sources that keep serving their last
+// known content when the upstream cannot be reached or its update is
+// refused. StaleReason says which step failed.
+type StaleReporter interface {
+	Stale() bool
+	StaleReason() string
+}
+
+// SignatureReporter is implemented by sources that verify commit signatures
+type SignatureReporter interface {
+	// LastVerification returns the most recent verification result, or nil
+	// if verification is disabled
+	LastVerification() *Verification
+}
+
+// Verification is the outcome of checking a commit's signature
+type Verification struct {
+	Commit    string
+	Verified  bool
+	Method    string
+	Signer    string
+	Error     string
+	CheckedAt time.Time
+}
+
+// FileRevision is a file's content at a revision that modified it
+type FileRevision struct {
+	Commit  string
+	Author  string
+	Time    time.Time
+	Content []byte
+}
@@ -0,0 +1,205 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// Event stream settings
+const (
+	// subscriberBuffer is how many events a slow subscriber may fall behind
+	// before it is disconnected to resume with Last-Event-ID
+	subscriberBuffer = 16
+	// heartbeatInterval keeps idle streams open through proxies
+	heartbeatInterval = 15 * time.Second
+)
+
+// Event is an applied commit as sent on the event stream. Its ID is the
+// commit hash.
+type Event struct {
+	ID   string
+	Data []byte // encoded domain.SyncEvent
+}
+
+// EventBroker fans applied commits out to event stream subscribers and
+// keeps the most recent ones so reconnecting clients can catch up
+type EventBroker struct {
+	mu          sync.Mutex
+	history     []Event // oldest first
+	size        int
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+// NewEventBroker creates a broker that keeps the last size events
+func NewEventBroker(size int) *EventBroker {
+	if size <= 0 {
+		size = 100
+	}
+	return &EventBroker{
+		size:        size,
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Publish announces an applied commit. Subscribers that are too far behind
+// are disconnected rather than blocking the sync.
+func (b *EventBroker) Publish(diff *domain.SyncDiff) error {
+	data, err := json.Marshal(domain.SyncEvent{
+		Commit:         diff.To,
+		PreviousCommit: diff.From,
+		SyncedAt:       diff.SyncedAt,
+		Added:          diff.Added,
+		Removed:        diff.Removed,
+		Modified:       diff.Modified,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	event := Event{ID: diff.To, Data: data}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.size {
+		b.history = b.history[len(b.history)-b.size:]
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns the retained events after lastID and a channel of new
+// ones, which is closed if the subscriber falls behind. found reports
+// whether lastID was in the history. cancel must be called when done.
+func (b *EventBroker) Subscribe(lastID string) (missed []Event, found bool, events <-chan Event, cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastID != "" {
+		for i := len(b.history) - 1; i >= 0; i-- {
+			if b.history[i].ID == lastID {
+				missed = append(missed, b.history[i+1:]...)
+				found = true
+				break
+			}
+		}
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	if b.closed {
+		close(ch)
+		return missed, found, ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return missed, found, ch, cancel
+}
+
+// Close ends every subscription, so open streams do not hold up a
+// graceful shutdown
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// EventStreamHandler serves applied commits as Server-Sent Events
+type EventStreamHandler struct {
+	broker   *EventBroker
+	revision func() string
+	logger   *slog.Logger
+}
+
+// NewEventStreamHandler creates an event stream handler. revision returns
+// the commit currently served.
+func NewEventStreamHandler(broker *EventBroker, revision func() string, logger *slog.Logger) *EventStreamHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &EventStreamHandler{
+		broker:   broker,
+		revision: revision,
+		logger:   logger,
+	}
+}
+
+// ServeHTTP streams a "sync" event per applied commit. A client resuming
+// with Last-Event-ID first receives the events it missed; if they are no
+// longer retained it receives a "reset" event and should re-list servers.
+func (h *EventStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// Streams outlive the server's write timeout
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	missed, found, events, cancel := h.broker.Subscribe(r.Header.Get("Last-Event-ID"))
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	current := h.revision()
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" && !found && lastID != current {
+		data, _ := json.Marshal(domain.SyncEvent{Commit: current})
+		writeEvent(w, "reset", Event{ID: current, Data: data})
+	}
+	for _, e := range missed {
+		writeEvent(w, "sync", e)
+	}
+	if err := rc.Flush(); err != nil {
+		h.logger.Warn("event stream not supported by response writer", "error", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				// Fell behind, or the server is shutting down
+				return
+			}
+			writeEvent(w, "sync", e)
+		case <-heartbeat.C:
+			_, _ = fmt.Fprint(w, ": keepalive\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, name string, e Event) {
+	_, _ = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, name, e.Data)
+}
@@ -0,0 +1,206 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcpregistry/server/internal/domain"
+)
+
+// publish announces a chain of commits, each following the previous one
+func publish(t *testing.T, b *EventBroker, ids ...string) {
+	t.Helper()
+	for i, id := range ids {
+		diff := &domain.SyncDiff{To: id}
+		if i > 0 {
+			diff.From = ids[i-1]
+		}
+		if err := b.Publish(diff); err != nil {
+			t.Fatalf("Publish(%s) error = %v", id, err)
+		}
+	}
+}
+
+func eventIDs(events []Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestEventBrokerSubscribe(t *testing.T) {
+	tests := []struct {
+		name       string
+		lastID     string
+		wantMissed []string
+		wantFound  bool
+	}{
+		{name: "no last event", lastID: ""},
+		{name: "resume from oldest retained", lastID: "c2", wantMissed: []string{"c3", "c4"}, wantFound: true},
+		{name: "resume from middle", lastID: "c3", wantMissed: []string{"c4"}, wantFound: true},
+		{name: "up to date", lastID: "c4", wantFound: true},
+		{name: "evicted from history", lastID: "c1"},
+		{name: "unknown commit", lastID: "deadbeef"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewEventBroker(3)
+			publish(t, b, "c1", "c2", "c3", "c4")
+
+			missed, found, _, cancel := b.Subscribe(tt.lastID)
+			defer cancel()
+
+			if got := eventIDs(missed); !slices.Equal(got, tt.wantMissed) {
+				t.Errorf("Subscribe(%q) missed = %v, want %v", tt.lastID, got, tt.wantMissed)
+			}
+			if found != tt.wantFound {
+				t.Errorf("Subscribe(%q) found = %v, want %v", tt.lastID, found, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestEventBrokerDelivery(t *testing.T) {
+	b := NewEventBroker(10)
+	_, _, events, cancel := b.Subscribe("")
+
+	publish(t, b, "c1")
+	if e := <-events; e.ID != "c1" {
+		t.Errorf("received %s, want c1", e.ID)
+	}
+
+	cancel()
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("channel open after cancel")
+	}
+}
+
+func TestEventBrokerSlowSubscriber(t *testing.T) {
+	b := NewEventBroker(100)
+	_, _, events, cancel := b.Subscribe("")
+	defer cancel()
+
+	ids := make([]string, subscriberBuffer+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("c%d", i)
+	}
+	publish(t, b, ids...)
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("received %d events before disconnect, want %d", received, subscriberBuffer)
+	}
+}
+
+func TestEventBrokerClose(t *testing.T) {
+	b := NewEventBroker(10)
+	_, _, before, cancel := b.Subscribe("")
+	defer cancel()
+
+	b.Close()
+	if _, ok := <-before; ok {
+		t.Error("subscription open after Close")
+	}
+	_, _, after, _ := b.Subscribe("")
+	if _, ok := <-after; ok {
+		t.Error("subscription made after Close is open")
+	}
+}
+
+func TestEventStreamResume(t *testing.T) {
+	tests := []struct {
+		name   string
+		lastID string
+		want   []string // id and event lines in order
+	}{
+		{
+			name: "new client",
+		},
+		{
+			name:   "resume",
+			lastID: "c2",
+			want:   []string{"id: c3", "event: sync", "id: c4", "event: sync"},
+		},
+		{
+			name:   "current commit",
+			lastID: "c4",
+		},
+		{
+			name:   "history lost",
+			lastID: "c0",
+			want:   []string{"id: c4", "event: reset"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewEventBroker(10)
+			publish(t, b, "c1", "c2", "c3", "c4")
+			h := NewEventStreamHandler(b, func() string { return "c4" }, nil)
+
+			// A cancelled request returns once the catch-up events are written
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			req := httptest.NewRequest(http.MethodGet, "/v0.1/events", nil).WithContext(ctx)
+			if tt.lastID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastID)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+				t.Errorf("Content-Type = %q, want text/event-stream", ct)
+			}
+			var got []string
+			for _, line := range strings.Split(rec.Body.String(), "\n") {
+				if strings.HasPrefix(line, "id: ") || strings.HasPrefix(line, "event: ") {
+					got = append(got, line)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("stream = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventPayload(t *testing.T) {
+	b := NewEventBroker(2)
+	publish(t, b, "c1")
+	err := b.Publish(&domain.SyncDiff{
+		From:     "c1",
+		To:       "c2",
+		SyncedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Added:    []string{"com.example/new"},
+		Removed:  []string{},
+		Modified: []string{"com.example/weather"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	missed, _, _, cancel := b.Subscribe("c1")
+	defer cancel()
+	if len(missed) != 1 {
+		t.Fatalf("missed %d events, want 1", len(missed))
+	}
+
+	// Field names follow the snake_case of the other JSON responses, and
+	// empty lists are left out
+	want := `{"commit":"c2","previous_commit":"c1","synced_at":"2025-01-01T00:00:00Z",` +
+		`"added":["com.example/new"],"modified":["com.example/weather"]}`
+	if got := string(missed[0].Data); got != want {
+		t.Errorf("event data = %s, want %s", got, want)
+	}
+}
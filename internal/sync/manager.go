@@ -6,17 +6,19 @@ import (
 	"sync"
 	"time"
 
-	"github.com/mcpregistry/server/internal/gitstore"
 	"github.com/mcpregistry/server/internal/registry"
+	"github.com/mcpregistry/server/internal/source"
 )
 
 // Manager handles repository synchronization
 type Manager struct {
-	store        *gitstore.Store
+	source       source.Source
 	registry     *registry.Registry
 	pollInterval time.Duration
+	staleRetry   time.Duration
 	debounce     time.Duration
 	logger       *slog.Logger
+	events       *EventBroker
 
 	triggerChan chan struct{}
 	mu          sync.Mutex
@@ -26,11 +28,18 @@ type Manager struct {
 
 // Config holds sync manager configuration
 type Config struct {
-	Store        *gitstore.Store
+	Source       source.Source
 	Registry     *registry.Registry
 	PollInterval time.Duration
 	Debounce     time.Duration
 	Logger       *slog.Logger
+
+	// StaleRetry is how often to retry while the source cannot reach upstream
+	StaleRetry time.Duration
+
+	// EventHistory is how many applied commits the event stream keeps for
+	// clients resuming with Last-Event-ID. Default 100.
+	EventHistory int
 }
 
 // NewManager creates a new sync manager
@@ -41,16 +50,21 @@ func NewManager(cfg Config) *Manager {
 	if cfg.Debounce <= 0 {
 		cfg.Debounce = 10 * time.Second
 	}
+	if cfg.StaleRetry <= 0 {
+		cfg.StaleRetry = 30 * time.Second
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = slog.Default()
 	}
 
 	return &Manager{
-		store:        cfg.Store,
+		source:       cfg.Source,
 		registry:     cfg.Registry,
 		pollInterval: cfg.PollInterval,
+		staleRetry:   cfg.StaleRetry,
 		debounce:     cfg.Debounce,
 		logger:       cfg.Logger,
+		events:       NewEventBroker(cfg.EventHistory),
 		triggerChan:  make(chan struct{}, 1),
 	}
 }
@@ -60,6 +74,9 @@ func (m *Manager) Start(ctx context.Context) {
 	ticker := time.NewTicker(m.pollInterval)
 	defer ticker.Stop()
 
+	staleTicker := time.NewTicker(m.staleRetry)
+	defer staleTicker.Stop()
+
 	m.logger.Info("sync manager started",
 		"poll_interval", m.pollInterval,
 		"debounce", m.debounce,
@@ -74,6 +91,11 @@ func (m *Manager) Start(ctx context.Context) {
 		case <-ticker.C:
 			m.doSync(ctx, "poll")
 
+		case <-staleTicker.C:
+			if m.sourceStale() {
+				m.doSync(ctx, "stale-retry")
+			}
+
 		case <-m.triggerChan:
 			// Debounce webhook triggers
 			m.debounceSync(ctx)
@@ -105,6 +127,21 @@ func (m *Manager) IsSyncing() bool {
 	return m.syncing
 }
 
+// sourceStale reports whether the source is serving content it could not
+// confirm with upstream
+func (m *Manager) sourceStale() bool {
+	sr, ok := m.source.(source.StaleReporter)
+	return ok && sr.Stale()
+}
+
+// lastVerification returns the source's latest signature check, if any
+func (m *Manager) lastVerification() *source.Verification {
+	if sr, ok := m.source.(source.SignatureReporter); ok {
+		return sr.LastVerification()
+	}
+	return nil
+}
+
 func (m *Manager) debounceSync(ctx context.Context) {
 	m.mu.Lock()
 	if time.Since(m.lastSync) < m.debounce {
@@ -136,8 +173,7 @@ func (m *Manager) doSync(ctx context.Context, source string) {
 	start := time.Now()
 	m.logger.Info("starting sync", "source", source)
 
-	// Pull with retry
-	changed, err := m.store.PullWithRetry(ctx, 3)
+	changed, err := m.source.Refresh(ctx)
 	if err != nil {
 		m.logger.Error("sync failed",
 			"source", source,
@@ -147,7 +183,10 @@ func (m *Manager) doSync(ctx context.Context, source string) {
 		return
 	}
 
-	if !changed {
+	// The source moves on before the registry builds its revision, so a
+	// revision that was rejected is rebuilt, and reported, on every sync
+	// until it is served or the source moves past it
+	if !changed && m.source.CurrentRevision() == m.registry.Revision() {
 		m.logger.Debug("no changes detected", "source", source)
 		m.mu.Lock()
 		m.lastSync = time.Now()
@@ -155,10 +194,13 @@ func (m *Manager) doSync(ctx context.Context, source string) {
 		return
 	}
 
-	// Refresh registry (reloads index and clears cache)
-	if err := m.registry.Refresh(); err != nil {
-		m.logger.Error("failed to refresh registry",
+	// Build and swap in a new snapshot; on failure the previous one stays live
+	diff, err := m.registry.Refresh()
+	if err != nil {
+		m.logger.Error("failed to refresh registry, serving previous snapshot",
 			"source", source,
+			"rejected_commit", m.source.CurrentRevision(),
+			"serving_commit", m.registry.Revision(),
 			"error", err,
 		)
 		return
@@ -168,10 +210,25 @@ func (m *Manager) doSync(ctx context.Context, source string) {
 	m.lastSync = time.Now()
 	m.mu.Unlock()
 
-	m.logger.Info("sync completed",
+	attrs := []any{
 		"source", source,
-		"commit", m.store.CurrentCommit(),
+		"commit", m.registry.Revision(),
 		"server_count", m.registry.ServerCount(),
 		"duration", time.Since(start),
-	)
+	}
+	if v := m.lastVerification(); v != nil {
+		attrs = append(attrs, "signature_method", v.Method, "signed_by", v.Signer)
+	}
+	m.logger.Info("sync completed", attrs...)
+
+	if diff != nil && diff.From != diff.To {
+		if err := m.events.Publish(diff); err != nil {
+			m.logger.Error("failed to publish sync event", "commit", diff.To, "error", err)
+		}
+	}
+}
+
+// Events returns the broker that announces applied commits
+func (m *Manager) Events() *EventBroker {
+	return m.events
 }
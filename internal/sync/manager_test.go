@@ -0,0 +1,213 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mcpregistry/server/internal/registry"
+	"github.com/mcpregistry/server/internal/source"
+)
+
+// memSource serves files from memory. Files pushed are picked up by the
+// next Refresh, the way a git source fetches a new commit.
+type memSource struct {
+	mu        sync.Mutex
+	files     map[string]string
+	pending   map[string]string
+	revision  int
+	failReads bool
+}
+
+func newMemSource(files map[string]string) *memSource {
+	return &memSource{files: files, revision: 1}
+}
+
+// push stages a new revision with files changed, or deleted if empty
+func (s *memSource) push(files map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := maps.Clone(s.files)
+	for name, content := range files {
+		if content == "" {
+			delete(next, name)
+			continue
+		}
+		next[name] = content
+	}
+	s.pending = next
+}
+
+func (s *memSource) setFailReads(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failReads = fail
+}
+
+func (s *memSource) ReadFile(path string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failReads {
+		return nil, errors.New("read failed")
+	}
+	content, ok := s.files[path]
+	if !ok {
+		return nil, fmt.Errorf("%s: file does not exist", path)
+	}
+	return []byte(content), nil
+}
+
+func (s *memSource) ListFiles(dir string) ([]string, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s *memSource) WalkFiles(dir string, match func(string) bool, fn func(string, []byte) error) error {
+	return errors.New("not supported")
+}
+
+func (s *memSource) CurrentRevision() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("r%d", s.revision)
+}
+
+func (s *memSource) Refresh(context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending == nil {
+		return false, nil
+	}
+	s.files, s.pending = s.pending, nil
+	s.revision++
+	return true, nil
+}
+
+func (s *memSource) Info() source.Info {
+	return source.Info{Type: "memory"}
+}
+
+func serverFile(name string) string {
+	return strings.Join([]string{
+		"$schema: https://static.modelcontextprotocol.io/schemas/2025-12-11/server.schema.json",
+		"name: " + name,
+		"description: Test server",
+		"version: 1.0.0",
+	}, "\n") + "\n"
+}
+
+func indexFile(names ...string) string {
+	var b strings.Builder
+	b.WriteString("servers:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  - name: %s\n    path: servers/%s.yaml\n", name, name)
+	}
+	return b.String()
+}
+
+func newTestManager(t *testing.T, src *memSource) (*Manager, *registry.Registry) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reg, err := registry.New(registry.Config{Store: src, Logger: logger})
+	if err != nil {
+		t.Fatalf("registry.New: %v", err)
+	}
+	if err := reg.LoadIndex(); err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	return NewManager(Config{Source: src, Registry: reg, Logger: logger}), reg
+}
+
+func TestManagerKeepsLastKnownGood(t *testing.T) {
+	src := newMemSource(map[string]string{
+		"index.yaml":     indexFile("a"),
+		"servers/a.yaml": serverFile("a"),
+	})
+	m, reg := newTestManager(t, src)
+	ctx := context.Background()
+	_, _, events, cancel := m.Events().Subscribe("")
+	defer cancel()
+
+	// A duplicate entry rejects the whole revision
+	src.push(map[string]string{"index.yaml": indexFile("a", "a")})
+	m.doSync(ctx, "test")
+
+	if got := reg.Revision(); got != "r1" {
+		t.Fatalf("serving %s after a rejected sync, want r1", got)
+	}
+	if _, err := reg.GetServer("a"); err != nil {
+		t.Errorf("GetServer after a rejected sync: %v", err)
+	}
+	rejection := reg.LastRejection()
+	if rejection == nil || rejection.Revision != "r2" {
+		t.Fatalf("LastRejection = %+v, want r2", rejection)
+	}
+
+	// Polls without upstream changes rebuild the rejected revision
+	m.doSync(ctx, "test")
+	if again := reg.LastRejection(); again == nil || again == rejection || again.Revision != "r2" {
+		t.Errorf("LastRejection after a retry = %+v, want r2 reported again", again)
+	}
+
+	select {
+	case e := <-events:
+		t.Fatalf("event %s published for a rejected sync", e.ID)
+	default:
+	}
+
+	src.push(map[string]string{
+		"index.yaml":     indexFile("a", "b"),
+		"servers/b.yaml": serverFile("b"),
+	})
+	m.doSync(ctx, "test")
+
+	if got := reg.Revision(); got != "r3" {
+		t.Fatalf("serving %s after a good sync, want r3", got)
+	}
+	if reg.LastRejection() != nil {
+		t.Error("rejection still reported after a good sync")
+	}
+	select {
+	case e := <-events:
+		if e.ID != "r3" {
+			t.Errorf("event %s published, want r3", e.ID)
+		}
+	default:
+		t.Error("no event published for the good sync")
+	}
+}
+
+func TestManagerRetriesUnbuiltRevision(t *testing.T) {
+	src := newMemSource(map[string]string{
+		"index.yaml":     indexFile("a"),
+		"servers/a.yaml": serverFile("a"),
+	})
+	m, reg := newTestManager(t, src)
+	ctx := context.Background()
+
+	// The source moves to r2, but the registry cannot read it
+	src.push(map[string]string{
+		"index.yaml":     indexFile("a", "b"),
+		"servers/b.yaml": serverFile("b"),
+	})
+	src.setFailReads(true)
+	m.doSync(ctx, "test")
+	if got := reg.Revision(); got != "r1" {
+		t.Fatalf("serving %s after a failed build, want r1", got)
+	}
+
+	// The next poll finds nothing new upstream but still builds r2
+	src.setFailReads(false)
+	m.doSync(ctx, "test")
+	if got := reg.Revision(); got != "r2" {
+		t.Fatalf("serving %s after the retry, want r2", got)
+	}
+	if _, err := reg.GetServer("b"); err != nil {
+		t.Errorf("GetServer(b) after the retry: %v", err)
+	}
+}
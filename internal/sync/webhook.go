@@ -8,6 +8,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"path"
 	"strings"
 )
 
@@ -15,7 +16,7 @@ import (
 type WebhookHandler struct {
 	secret  []byte
 	manager *Manager
-	branch  string
+	ref     string
 	logger  *slog.Logger
 }
 
@@ -24,6 +25,7 @@ type PushEvent struct {
 	Ref        string `json:"ref"`
 	Before     string `json:"before"`
 	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
 	Repository struct {
 		FullName string `json:"full_name"`
 		CloneURL string `json:"clone_url"`
@@ -41,15 +43,16 @@ type PushEvent struct {
 	} `json:"commits"`
 }
 
-// NewWebhookHandler creates a new webhook handler
-func NewWebhookHandler(secret string, manager *Manager, branch string, logger *slog.Logger) *WebhookHandler {
+// NewWebhookHandler creates a new webhook handler. Pushes to refs matching
+// the ref pattern (e.g. "refs/heads/main" or "refs/tags/v*") trigger a sync.
+func NewWebhookHandler(secret string, manager *Manager, ref string, logger *slog.Logger) *WebhookHandler {
 	if logger == nil {
 		logger = slog.Default()
 	}
 	return &WebhookHandler{
 		secret:  []byte(secret),
 		manager: manager,
-		branch:  branch,
+		ref:     ref,
 		logger:  logger,
 	}
 }
@@ -104,20 +107,27 @@ func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if push is to our branch
-	expectedRef := "refs/heads/" + h.branch
-	if event.Ref != expectedRef {
-		h.logger.Debug("ignoring push to different branch",
+	// Check if push is to a tracked ref
+	if ok, _ := path.Match(h.ref, event.Ref); !ok {
+		h.logger.Debug("ignoring push to untracked ref",
 			"ref", event.Ref,
-			"expected", expectedRef,
+			"expected", h.ref,
 		)
 		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"status": "ignored", "reason": "different branch"}`))
+		_, _ = w.Write([]byte(`{"status": "ignored", "reason": "untracked ref"}`))
+		return
+	}
+
+	// Deleting a tracked ref never moves us forward
+	if event.Deleted {
+		h.logger.Debug("ignoring ref deletion", "ref", event.Ref)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status": "ignored", "reason": "ref deleted"}`))
 		return
 	}
 
 	// Log commit info
-	h.logger.Info("push event for tracked branch",
+	h.logger.Info("push event for tracked ref",
 		"ref", event.Ref,
 		"before", event.Before[:8],
 		"after", event.After[:8],
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookHandlerRefs(t *testing.T) {
+	const secret = "webhook-secret"
+	const before = "1111111111111111111111111111111111111111"
+	const after = "2222222222222222222222222222222222222222"
+
+	tests := []struct {
+		name        string
+		ref         string // pattern the handler tracks
+		pushed      string
+		deleted     bool
+		event       string
+		signature   string // computed when empty
+		wantStatus  int
+		wantTrigger bool
+	}{
+		{name: "tracked tag", ref: "refs/tags/v*", pushed: "refs/tags/v1.2.0", wantStatus: http.StatusOK, wantTrigger: true},
+		{name: "prerelease tag", ref: "refs/tags/v*", pushed: "refs/tags/v1.3.0-rc.1", wantStatus: http.StatusOK, wantTrigger: true},
+		{name: "untracked tag", ref: "refs/tags/v*", pushed: "refs/tags/nightly", wantStatus: http.StatusOK},
+		{name: "branch in tag mode", ref: "refs/tags/v*", pushed: "refs/heads/v1", wantStatus: http.StatusOK},
+		{name: "deleted tag", ref: "refs/tags/v*", pushed: "refs/tags/v1.2.0", deleted: true, wantStatus: http.StatusOK},
+		{name: "nested tag pattern", ref: "refs/tags/release/*", pushed: "refs/tags/release/2026.01", wantStatus: http.StatusOK, wantTrigger: true},
+		{name: "tracked branch", ref: "refs/heads/main", pushed: "refs/heads/main", wantStatus: http.StatusOK, wantTrigger: true},
+		{name: "other branch", ref: "refs/heads/main", pushed: "refs/heads/feature", wantStatus: http.StatusOK},
+		{name: "not a push", ref: "refs/tags/v*", pushed: "refs/tags/v1.2.0", event: "release", wantStatus: http.StatusOK},
+		{name: "bad signature", ref: "refs/tags/v*", pushed: "refs/tags/v1.2.0", signature: "sha256=00", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(Config{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+			h := NewWebhookHandler(secret, m, tt.ref, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			body := fmt.Sprintf(`{"ref":%q,"before":%q,"after":%q,"deleted":%t}`, tt.pushed, before, after, tt.deleted)
+			signature := tt.signature
+			if signature == "" {
+				mac := hmac.New(sha256.New, []byte(secret))
+				mac.Write([]byte(body))
+				signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			}
+			event := tt.event
+			if event == "" {
+				event = "push"
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+			req.Header.Set("X-Hub-Signature-256", signature)
+			req.Header.Set("X-GitHub-Event", event)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			triggered := len(m.triggerChan) == 1
+			if triggered != tt.wantTrigger {
+				t.Errorf("triggered = %t, want %t (response %s)", triggered, tt.wantTrigger, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+#!/bin/bash
+# Downloads the official server.json schemas into internal/schema/schemas
+# Usage: scripts/fetch-schemas.sh [date...]
+# Without arguments, every revision the service can convert is fetched.
+# After replacing a hand-written file, remove its date from the provisional
+# list in internal/schema/schema.go.
+
+set -e
+
+cd "$(dirname "$0")/.."
+
+BASE_URL="https://static.modelcontextprotocol.io/schemas"
+DATES=("$@")
+if [ ${#DATES[@]} -eq 0 ]; then
+  DATES=(2025-07-09 2025-09-29 2025-12-11)
+fi
+
+for date in "${DATES[@]}"; do
+  dir="internal/schema/schemas/$date"
+  mkdir -p "$dir"
+  echo "Fetching $BASE_URL/$date/server.schema.json"
+  curl -fsSL -o "$dir/server.schema.json" "$BASE_URL/$date/server.schema.json"
+done
+
+go test ./internal/schema